@@ -0,0 +1,253 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package cache keeps parsed commit notes and commit stats for reporting
+// in the gtm home directory so notes are not parsed and diffed on every report.
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/gob"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/scm"
+)
+
+const (
+	// Dir is the name of the cache directory within the gtm home directory
+	Dir = "cache"
+	// FileExt is the file extension of a repository's cache file
+	FileExt = ".cache"
+	// version changes when the format of cache entries changes, older caches are discarded
+	version = 1
+	// DisableEnv is the environment variable which turns off the cache when it is set
+	DisableEnv = "GTM_NO_CACHE"
+)
+
+// Entry is a commit's parsed note and stats, it is keyed by the commit id and the note's blob id
+type Entry struct {
+	Note note.CommitNote
+	// Stats are only set if HasStats is true
+	Stats    scm.CommitStats
+	HasStats bool
+}
+
+type nameSpace struct {
+	// Ref is the commit the notes ref pointed to when the entries were validated
+	Ref     string
+	Entries map[string]Entry
+}
+
+// Notes is the cache for a repository's notes, methods can be called on a nil cache which never has entries
+type Notes struct {
+	Version    int
+	RepoPath   string
+	NameSpaces map[string]*nameSpace
+
+	path    string
+	changed bool
+}
+
+// DirPath returns the cache directory, i.e. ~/.git-time-metric/cache
+func DirPath() (string, error) {
+	d, err := project.HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, Dir), nil
+}
+
+// Path returns the cache file within dir for the repository in repoPath
+func Path(dir, repoPath string) (string, error) {
+	abs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("%x%s", sha1.Sum([]byte(abs)), FileExt)), nil
+}
+
+// Open returns the cache within dir for the repository in repoPath,
+// an empty cache is returned if the cache does not exist, is unreadable or is from another version
+func Open(dir, repoPath string) (*Notes, error) {
+	p, err := Path(dir, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	empty := &Notes{Version: version, RepoPath: abs, NameSpaces: map[string]*nameSpace{}, path: p}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return empty, nil
+	}
+	defer f.Close()
+
+	n := &Notes{}
+	if err := gob.NewDecoder(f).Decode(n); err != nil || n.Version != version || n.RepoPath != abs {
+		return empty, nil
+	}
+	n.path = p
+	if n.NameSpaces == nil {
+		n.NameSpaces = map[string]*nameSpace{}
+	}
+	return n, nil
+}
+
+func key(commitID, noteID string) string {
+	return commitID + ":" + noteID
+}
+
+// NoteLookup finds a namespace's notes ref and the note for a commit, it is implemented by scm.NoteReader
+type NoteLookup interface {
+	Ref(nameSpace string) (string, error)
+	NoteID(commitID string, nameSpace string) (string, error)
+}
+
+// Validate removes entries for notes that have changed or were removed if the namespace's notes ref has moved,
+// i.e. after a rebase and git notes prune
+func (n *Notes) Validate(ns string, r NoteLookup) error {
+	if n == nil {
+		return nil
+	}
+
+	ref, err := r.Ref(ns)
+	if err != nil {
+		return err
+	}
+	c, ok := n.NameSpaces[ns]
+	if !ok {
+		n.NameSpaces[ns] = &nameSpace{Ref: ref, Entries: map[string]Entry{}}
+		n.changed = true
+		return nil
+	}
+	if c.Ref == ref {
+		return nil
+	}
+
+	for k := range c.Entries {
+		commitID := strings.SplitN(k, ":", 2)[0]
+		noteID, err := r.NoteID(commitID, ns)
+		if err != nil {
+			return err
+		}
+		if noteID == "" || k != key(commitID, noteID) {
+			delete(c.Entries, k)
+		}
+	}
+	c.Ref = ref
+	n.changed = true
+	return nil
+}
+
+// Get returns the entry for a commit and the id of its note's blob
+func (n *Notes) Get(ns, commitID, noteID string) (Entry, bool) {
+	if n == nil {
+		return Entry{}, false
+	}
+	c, ok := n.NameSpaces[ns]
+	if !ok {
+		return Entry{}, false
+	}
+	e, ok := c.Entries[key(commitID, noteID)]
+	return e, ok
+}
+
+// Put saves the entry for a commit and the id of its note's blob, Validate must be called for the namespace first
+func (n *Notes) Put(ns, commitID, noteID string, e Entry) {
+	if n == nil {
+		return
+	}
+	c, ok := n.NameSpaces[ns]
+	if !ok {
+		return
+	}
+	c.Entries[key(commitID, noteID)] = e
+	n.changed = true
+}
+
+// Save writes the cache if it has changed
+func (n *Notes) Save() error {
+	if n == nil || !n.changed {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(n.path), 0700); err != nil {
+		return err
+	}
+
+	// write to a temporary file so concurrent reports do not read a partial cache
+	f, err := ioutil.TempFile(filepath.Dir(n.path), filepath.Base(n.path))
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(n); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), n.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	n.changed = false
+	return nil
+}
+
+// Stat is the size of a repository's cache
+type Stat struct {
+	RepoPath string
+	Entries  int
+	Bytes    int64
+}
+
+// Stats returns the size of each repository's cache within dir, sorted by cache file
+func Stats(dir string) ([]Stat, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+FileExt))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := []Stat{}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		stat := Stat{Bytes: info.Size()}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		n := &Notes{}
+		if err := gob.NewDecoder(f).Decode(n); err == nil && n.Version == version {
+			stat.RepoPath = n.RepoPath
+			for _, c := range n.NameSpaces {
+				stat.Entries += len(c.Entries)
+			}
+		}
+		f.Close()
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// Clear removes the cache within dir for all repositories
+func Clear(dir string) error {
+	return os.RemoveAll(dir)
+}
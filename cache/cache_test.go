@@ -0,0 +1,149 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/scm"
+)
+
+// lookup is a namespace's notes ref and note blob ids by commit id
+type lookup struct {
+	ref   string
+	notes map[string]string
+}
+
+func (l lookup) Ref(nameSpace string) (string, error) { return l.ref, nil }
+
+func (l lookup) NoteID(commitID string, nameSpace string) (string, error) {
+	return l.notes[commitID], nil
+}
+
+func TestNotes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gtm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repoPath := filepath.Join(dir, "repo")
+
+	entry := Entry{
+		Note:     note.CommitNote{Files: []note.FileDetail{{SourceFile: "main.go", TimeSpent: 60, Timeline: map[int64]int{1435676400: 60}, Status: "m"}}},
+		Stats:    scm.CommitStats{Insertions: 3, Deletions: 1, FilesChanged: 1, Files: []string{"main.go"}},
+		HasStats: true,
+	}
+	notes := lookup{ref: "r1", notes: map[string]string{"c1": "n1", "c2": "n2"}}
+
+	c, err := Open(dir, repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Validate("gtm-data", notes); err != nil {
+		t.Fatal(err)
+	}
+	c.Put("gtm-data", "c1", "n1", entry)
+	c.Put("gtm-data", "c2", "n2", Entry{})
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = Open(dir, repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Validate("gtm-data", notes); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := c.Get("gtm-data", "c1", "n1")
+	if !ok || got.Note.Total() != 60 || got.Stats.Insertions != 3 || !got.HasStats {
+		t.Errorf("Get(c1, n1), want %+v got %+v %t", entry, got, ok)
+	}
+	if _, ok := c.Get("gtm-data", "c1", "n9"); ok {
+		t.Errorf("Get(c1, n9), want no entry for a different note got one")
+	}
+	if _, ok := c.Get("gtm-local", "c1", "n1"); ok {
+		t.Errorf("Get(gtm-local), want no entry for a different namespace got one")
+	}
+
+	// the ref moved because c2's note was edited
+	notes = lookup{ref: "r2", notes: map[string]string{"c1": "n1", "c2": "n3"}}
+	if err := c.Validate("gtm-data", notes); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("gtm-data", "c1", "n1"); !ok {
+		t.Errorf("Validate(), want unchanged c1 kept")
+	}
+	if entries := len(c.NameSpaces["gtm-data"].Entries); entries != 1 {
+		t.Errorf("Validate(), want 1 entry after c2's note changed got %d", entries)
+	}
+	c.Put("gtm-data", "c3", "", Entry{})
+
+	// the ref moved because c3 was rebased away and its note pruned, commits without notes are removed too
+	notes = lookup{ref: "r3", notes: map[string]string{"c1": "n1"}}
+	if err := c.Validate("gtm-data", notes); err != nil {
+		t.Fatal(err)
+	}
+	if entries := len(c.NameSpaces["gtm-data"].Entries); entries != 1 {
+		t.Errorf("Validate(), want 1 entry after c2 and c3 were removed got %d", entries)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := Stats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0].RepoPath != repoPath || stats[0].Entries != 1 || stats[0].Bytes == 0 {
+		t.Errorf("Stats(), want 1 entry for %s got %+v", repoPath, stats)
+	}
+
+	if err := Clear(dir); err != nil {
+		t.Fatal(err)
+	}
+	if stats, err := Stats(dir); err != nil || len(stats) != 0 {
+		t.Errorf("Stats() after Clear(), want none got %+v, %v", stats, err)
+	}
+}
+
+func TestNilNotes(t *testing.T) {
+	var c *Notes
+	if err := c.Validate("gtm-data", lookup{}); err != nil {
+		t.Errorf("Validate() on nil cache, want nil got %s", err)
+	}
+	c.Put("gtm-data", "c1", "n1", Entry{})
+	if _, ok := c.Get("gtm-data", "c1", "n1"); ok {
+		t.Errorf("Get() on nil cache, want no entry got one")
+	}
+	if err := c.Save(); err != nil {
+		t.Errorf("Save() on nil cache, want nil got %s", err)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gtm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p, err := Path(dir, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(p, []byte("not a cache"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Open(dir, "repo")
+	if err != nil || c == nil || len(c.NameSpaces) != 0 {
+		t.Errorf("Open() an invalid cache, want an empty cache got %+v, %v", c, err)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/git-time-metric/gtm/cache"
+	"github.com/mitchellh/cli"
+)
+
+// CacheClearCmd contains methods for the cache clear command
+type CacheClearCmd struct {
+	UI cli.Ui
+}
+
+// NewCacheClear returns new CacheClearCmd struct
+func NewCacheClear() (cli.Command, error) {
+	return CacheClearCmd{}, nil
+}
+
+// Help returns help for the cache clear command
+func (c CacheClearCmd) Help() string {
+	helpText := `
+Usage: gtm cache clear
+
+  Remove the cached notes and commit stats for all repositories.
+
+  The cache is rebuilt as reports are run, set GTM_NO_CACHE to report without the cache.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run executes cache clear command with args
+func (c CacheClearCmd) Run(args []string) int {
+	cmdFlags := flag.NewFlagSet("cache clear", flag.ContinueOnError)
+	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	dir, err := cache.DirPath()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if err := cache.Clear(dir); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	c.UI.Output(fmt.Sprintf("Cache cleared in %s", dir))
+	return 0
+}
+
+// Synopsis return help for cache clear command
+func (c CacheClearCmd) Synopsis() string {
+	return "Remove cached notes for all repositories"
+}
+
+// CacheStatsCmd contains methods for the cache stats command
+type CacheStatsCmd struct {
+	UI cli.Ui
+}
+
+// NewCacheStats returns new CacheStatsCmd struct
+func NewCacheStats() (cli.Command, error) {
+	return CacheStatsCmd{}, nil
+}
+
+// Help returns help for the cache stats command
+func (c CacheStatsCmd) Help() string {
+	helpText := `
+Usage: gtm cache stats
+
+  Show the number of cached notes and the size of the cache for each repository.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run executes cache stats command with args
+func (c CacheStatsCmd) Run(args []string) int {
+	cmdFlags := flag.NewFlagSet("cache stats", flag.ContinueOnError)
+	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	dir, err := cache.DirPath()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	stats, err := cache.Stats(dir)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	var entries int
+	var bytes int64
+	for _, s := range stats {
+		repoPath := s.RepoPath
+		if repoPath == "" {
+			repoPath = "(invalid cache)"
+		}
+		c.UI.Output(fmt.Sprintf("%8d notes %10d bytes  %s", s.Entries, s.Bytes, repoPath))
+		entries += s.Entries
+		bytes += s.Bytes
+	}
+	c.UI.Output(fmt.Sprintf("%8d notes %10d bytes  total in %s", entries, bytes, dir))
+	return 0
+}
+
+// Synopsis return help for cache stats command
+func (c CacheStatsCmd) Synopsis() string {
+	return "Show cached notes for each repository"
+}
@@ -35,7 +35,7 @@ Options:
   -yes                       Delete time data without asking for confirmation.
   -terminal-only             Only delete terminal time data
   -app-only                  Only delete apps time data
-  -days=0                    Delete starting from n days in the past
+  -days=0                    Delete starting from n days in the past, days are in the configured time zone
 `
 	return strings.TrimSpace(helpText)
 }
@@ -54,6 +54,17 @@ func (c CleanCmd) Run(args []string) int {
 		return 1
 	}
 
+	userCfg, err := project.LoadUserConfig()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	loc, err := util.ParseTimeZone(userCfg.TimeZone)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	confirm := yes
 	if !confirm {
 		response, err := c.UI.Ask("Delete pending time data (y/n)?")
@@ -64,7 +75,7 @@ func (c CleanCmd) Run(args []string) int {
 	}
 
 	if confirm {
-		if err := project.Clean(util.AfterNow(days), terminalOnly, appOnly); err != nil {
+		if err := project.Clean(util.AfterNow(days, loc), terminalOnly, appOnly); err != nil {
 			c.UI.Error(err.Error())
 			return 1
 		}
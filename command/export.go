@@ -0,0 +1,201 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/report"
+	"github.com/git-time-metric/gtm/scm"
+	"github.com/git-time-metric/gtm/util"
+	"github.com/mitchellh/cli"
+)
+
+// ExportCmd contains methods for export command
+type ExportCmd struct {
+	UI cli.Ui
+}
+
+// NewExport returns new ExportCmd struct
+func NewExport() (cli.Command, error) {
+	return ExportCmd{}, nil
+}
+
+// Help returns help for export command
+func (c ExportCmd) Help() string {
+	helpText := `
+Usage: gtm export -output ics [options]
+
+  Export work sessions reconstructed from the timelines of commits.
+
+  Committed time is recorded by hour, time spent in adjacent hours is merged into one session and a
+  session ends when the time without time spent is longer than the gap. Each session is an event titled
+  with its projects and commit subjects, i.e. 'gtm export -output ics > gtm.ics' to import into a calendar.
+
+Options:
+
+  -output=ics                Specify output [ics]
+  -gap=15                    Minutes without time spent that end a session, committed time is by hour so it is only split at idle hours
+  -terminal-off=false        Exclude time spent in terminal (Terminal plug-in is required)
+  -app-off=false             Exclude time spent in apps
+  -tz=local                  Time zone for commit limiting [local|utc|<zone name>, i.e. America/Chicago]
+                             The default can be set with timeZone in ~/.git-time-metric/config.json
+
+  Date Range:
+
+  -from-date=yyyy-mm-dd      Export commits starting from this date
+  -to-date=yyyy-mm-dd        Export commits thru the end of this date
+  -this-month=false          Export commits for this month, this is the default
+  -last-month=false          Export commits for last month
+  -this-week=false           Export commits for this week
+  -last-week=false           Export commits for last week
+  -this-year=false           Export commits for this year
+  -last-year=false           Export commits for last year
+  -author=""                 Export commits which contain author name or email substring
+  -time-spent=false          Export the time spent within the date range instead of the commits made within it
+  -lookback=30               Days before the date range to search for commits with -time-spent, i.e. for long-running branches
+
+  Multi-Project Export:
+
+  -tags=""                   Project tags to export, i.e --tags tag1,tag2
+  -all=false                 Export all projects
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run executes export command with args
+func (c ExportCmd) Run(args []string) int {
+	var lookback, gap int
+	var terminalOff, appOff, all, timeSpent bool
+	var thisWeek, lastWeek, thisMonth, lastMonth, thisYear, lastYear bool
+	var fromDate, toDate, author, tags, tz, output string
+	cmdFlags := flag.NewFlagSet("export", flag.ContinueOnError)
+	cmdFlags.StringVar(&output, "output", report.OutputICS, "")
+	cmdFlags.IntVar(&gap, "gap", report.DefaultSessionGap/60, "")
+	cmdFlags.BoolVar(&terminalOff, "terminal-off", false, "")
+	cmdFlags.BoolVar(&appOff, "app-off", false, "")
+	cmdFlags.StringVar(&tz, "tz", "", "")
+	cmdFlags.StringVar(&fromDate, "from-date", "", "")
+	cmdFlags.StringVar(&toDate, "to-date", "", "")
+	cmdFlags.BoolVar(&thisWeek, "this-week", false, "")
+	cmdFlags.BoolVar(&lastWeek, "last-week", false, "")
+	cmdFlags.BoolVar(&thisMonth, "this-month", false, "")
+	cmdFlags.BoolVar(&lastMonth, "last-month", false, "")
+	cmdFlags.BoolVar(&thisYear, "this-year", false, "")
+	cmdFlags.BoolVar(&lastYear, "last-year", false, "")
+	cmdFlags.StringVar(&author, "author", "", "")
+	cmdFlags.BoolVar(&timeSpent, "time-spent", false, "")
+	cmdFlags.IntVar(&lookback, "lookback", 30, "")
+	cmdFlags.StringVar(&tags, "tags", "", "")
+	cmdFlags.BoolVar(&all, "all", false, "")
+	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	if output != report.OutputICS {
+		c.UI.Error(fmt.Sprintf("export --output=%s not valid\n", output))
+		return 1
+	}
+
+	if gap < 1 {
+		c.UI.Error(fmt.Sprintf("export --gap=%d not valid\n", gap))
+		return 1
+	}
+
+	if lookback < 0 {
+		c.UI.Error(fmt.Sprintf("export --lookback=%d not valid\n", lookback))
+		return 1
+	}
+
+	userCfg, err := project.LoadUserConfig()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if tz == "" {
+		tz = userCfg.TimeZone
+	}
+	loc, err := util.ParseTimeZone(tz)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	weekday := time.Sunday
+	if userCfg.WeekStart != "" {
+		if weekday, err = util.ParseWeekday(userCfg.WeekStart); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
+	index, err := project.NewIndex()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	tagList := []string{}
+	if tags != "" {
+		tagList = util.Map(strings.Split(tags, ","), strings.TrimSpace)
+	}
+	projects, err := index.Get(tagList, all)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// exports are for this month unless a date range is specified
+	if !(fromDate != "" || toDate != "" || thisWeek || lastWeek || thisMonth || lastMonth || thisYear || lastYear) {
+		thisMonth = true
+	}
+
+	// set max to absurdly high value for number of possible commits
+	limiter, err := scm.NewCommitLimiter(
+		2147483647, fromDate, toDate, author, "",
+		false, false, thisWeek, lastWeek,
+		thisMonth, lastMonth, thisYear, lastYear, weekday, loc)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	limiter.TimeSpent = timeSpent
+	limiter.Lookback = time.Duration(lookback) * 24 * time.Hour
+
+	projCommits := []report.ProjectCommits{}
+	for _, p := range projects {
+		commits, err := scm.CommitIDs(limiter, p)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		projCommits = append(projCommits, report.ProjectCommits{Path: p, Commits: commits})
+	}
+
+	out, err := report.Export(projCommits,
+		report.OutputOptions{
+			TerminalOff: terminalOff,
+			AppOff:      appOff,
+			Location:    loc,
+			Output:      output,
+			DateRange:   limiter.DateRange,
+			TimeSpent:   timeSpent,
+			SessionGap:  gap * 60})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	c.UI.Output(out)
+	return 0
+}
+
+// Synopsis return help for export command
+func (c ExportCmd) Synopsis() string {
+	return "Export work sessions from git repositories to a calendar"
+}
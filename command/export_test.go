@@ -0,0 +1,67 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/util"
+	"github.com/mitchellh/cli"
+)
+
+func TestExport(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	// gtm files must only be created in the test repo
+	if err := os.Chdir(repo.Workdir()); err != nil {
+		t.Fatal(err)
+	}
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	ui := new(cli.MockUi)
+	c := ExportCmd{UI: ui}
+
+	args := []string{"-output", "ics", "-from-date", "2016-03-20", "-tz", "utc"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm export(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+
+	for _, want := range []string{"BEGIN:VEVENT", "DTSTART:20160320T180000Z", "event/event.go"} {
+		if !strings.Contains(ui.OutputWriter.String(), want) {
+			t.Errorf("gtm export(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+		}
+	}
+}
+
+func TestExportInvalidOutput(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := ExportCmd{UI: ui}
+
+	args := []string{"-output", "csv"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm export(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "export --output=csv not valid") {
+		t.Errorf("gtm export(%+v), want 'export --output=csv not valid' got %s", args, ui.ErrorWriter.String())
+	}
+}
@@ -6,8 +6,10 @@ package command
 
 import (
 	"flag"
+	"fmt"
 	"strings"
 
+	"github.com/git-time-metric/gtm/note"
 	"github.com/git-time-metric/gtm/project"
 	"github.com/git-time-metric/gtm/util"
 
@@ -38,23 +40,36 @@ Options:
   -tags=tag1,tag2            Add tags to projects, multiple calls appends tags.
 
   -clear-tags                Clear all tags.
+
+  -privacy=full              Detail saved in shared time notes [full|files|hashed|totals].
+                             files excludes timelines, hashed hashes file paths, totals saves only the total time.
+                             Paths are hashed with a secret kept in .gtm/privacy.secret, hashes only match within a project.
+
+  -keep-local=false          If privacy is not full, also save full detail notes in an unshared notes namespace.
 `
 	return strings.TrimSpace(helpText)
 }
 
 // Run executes init command with args
 func (c InitCmd) Run(args []string) int {
-	var terminal, clearTags bool
-	var tags string
+	var terminal, clearTags, keepLocal bool
+	var tags, privacy string
 	cmdFlags := flag.NewFlagSet("init", flag.ContinueOnError)
 	cmdFlags.BoolVar(&terminal, "terminal", true, "")
 	cmdFlags.BoolVar(&clearTags, "clear-tags", false, "")
 	cmdFlags.StringVar(&tags, "tags", "", "")
+	cmdFlags.StringVar(&privacy, "privacy", "", "")
+	cmdFlags.BoolVar(&keepLocal, "keep-local", false, "")
 	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
 	}
-	m, err := project.Initialize(terminal, util.Map(strings.Split(tags, ","), strings.TrimSpace), clearTags)
+	if privacy != "" && !util.StringInSlice(note.PrivacyLevels, privacy) {
+		c.UI.Error(fmt.Sprintf("init --privacy=%s not valid\n", privacy))
+		return 1
+	}
+	m, err := project.Initialize(
+		terminal, util.Map(strings.Split(tags, ","), strings.TrimSpace), clearTags, privacy, keepLocal)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return 1
@@ -45,7 +45,7 @@ func TestInitDefaultOptions(t *testing.T) {
      post-commit: gtm commit --yes
   alias.fetchgtm: fetch origin refs/notes/gtm-data:refs/notes/gtm-data
    alias.pushgtm: push origin refs/notes/gtm-data
-notes.rewriteref: refs/notes/gtm-data
+notes.rewriteref: refs/notes/gtm-data*
         terminal: true
       .gitignore: /.gtm/
             tags:
@@ -0,0 +1,248 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/report"
+	"github.com/git-time-metric/gtm/scm"
+	"github.com/git-time-metric/gtm/util"
+	"github.com/mitchellh/cli"
+)
+
+// InvoiceCmd contains methods for invoice command
+type InvoiceCmd struct {
+	UI cli.Ui
+}
+
+// NewInvoice returns new InvoiceCmd struct
+func NewInvoice() (cli.Command, error) {
+	return InvoiceCmd{}, nil
+}
+
+// Help returns help for invoice command
+func (c InvoiceCmd) Help() string {
+	helpText := `
+Usage: gtm invoice [options]
+
+  Display billable time and amounts by project and commit subject.
+
+  Hourly rates are read from ~/.git-time-metric/rates.json, for example
+
+  {
+    "default": {"hourly": 100, "currency": "USD"},
+    "projects": {"gtm": {"hourly": 120}},
+    "tags": {"client-a": {"hourly": 150, "currency": "EUR"}},
+    "authors": {"jane@example.com": {"hourly": 90}},
+    "rounding": {"per": "commit", "increment": 15, "minimum": 30}
+  }
+
+  A project's rate takes precedence over a tag's rate, then an author's rate and then the default rate.
+
+Options:
+
+  -rates=""                  Billing rates file, defaults to ~/.git-time-metric/rates.json
+  -round-per=""              Round billable time for each commit or for each line item by day [commit|day], overrides the rates file
+                             Time is rounded for each commit when only -increment or -minimum is given
+  -increment=0               Round billable time up to the next increment of minutes, overrides the rates file
+  -minimum=0                 Minimum billable minutes when rounding, overrides the rates file
+  -output=text               Specify output [text|json|csv]
+  -terminal-off=false        Exclude time spent in terminal (Terminal plug-in is required)
+  -app-off=false             Exclude time spent in apps
+  -force-color=false         Always output color even if no terminal is detected
+  -tz=local                  Time zone for dates and commit limiting [local|utc|<zone name>, i.e. America/Chicago]
+                             The default can be set with timeZone in ~/.git-time-metric/config.json
+
+  Date Range:
+
+  -from-date=yyyy-mm-dd      Bill commits starting from this date
+  -to-date=yyyy-mm-dd        Bill commits thru the end of this date
+  -this-month=false          Bill commits for this month, this is the default
+  -last-month=false          Bill commits for last month
+  -this-week=false           Bill commits for this week
+  -last-week=false           Bill commits for last week
+  -this-year=false           Bill commits for this year
+  -last-year=false           Bill commits for last year
+  -author=""                 Bill commits which contain author name or email substring
+  -time-spent=false          Bill the time spent within the date range instead of the commits made within it
+  -lookback=30               Days before the date range to search for commits with -time-spent, i.e. for long-running branches
+
+  Multi-Project Invoicing:
+
+  -tags=""                   Project tags to invoice, i.e --tags tag1,tag2
+  -all=false                 Invoice all projects
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run executes invoice command with args
+func (c InvoiceCmd) Run(args []string) int {
+	var increment, minimum, lookback int
+	var color, terminalOff, appOff, all, timeSpent bool
+	var thisWeek, lastWeek, thisMonth, lastMonth, thisYear, lastYear bool
+	var fromDate, toDate, author, tags, tz, output, ratesFile, roundPer string
+	cmdFlags := flag.NewFlagSet("invoice", flag.ContinueOnError)
+	cmdFlags.StringVar(&ratesFile, "rates", "", "")
+	cmdFlags.StringVar(&roundPer, "round-per", "", "")
+	cmdFlags.IntVar(&increment, "increment", 0, "")
+	cmdFlags.IntVar(&minimum, "minimum", 0, "")
+	cmdFlags.StringVar(&output, "output", report.OutputText, "")
+	cmdFlags.BoolVar(&color, "force-color", false, "")
+	cmdFlags.BoolVar(&terminalOff, "terminal-off", false, "")
+	cmdFlags.BoolVar(&appOff, "app-off", false, "")
+	cmdFlags.StringVar(&tz, "tz", "", "")
+	cmdFlags.StringVar(&fromDate, "from-date", "", "")
+	cmdFlags.StringVar(&toDate, "to-date", "", "")
+	cmdFlags.BoolVar(&thisWeek, "this-week", false, "")
+	cmdFlags.BoolVar(&lastWeek, "last-week", false, "")
+	cmdFlags.BoolVar(&thisMonth, "this-month", false, "")
+	cmdFlags.BoolVar(&lastMonth, "last-month", false, "")
+	cmdFlags.BoolVar(&thisYear, "this-year", false, "")
+	cmdFlags.BoolVar(&lastYear, "last-year", false, "")
+	cmdFlags.StringVar(&author, "author", "", "")
+	cmdFlags.BoolVar(&timeSpent, "time-spent", false, "")
+	cmdFlags.IntVar(&lookback, "lookback", 30, "")
+	cmdFlags.StringVar(&tags, "tags", "", "")
+	cmdFlags.BoolVar(&all, "all", false, "")
+	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	if !util.StringInSlice([]string{report.OutputText, report.OutputJSON, report.OutputCSV}, output) {
+		c.UI.Error(fmt.Sprintf("invoice --output=%s not valid\n", output))
+		return 1
+	}
+
+	if lookback < 0 {
+		c.UI.Error(fmt.Sprintf("invoice --lookback=%d not valid\n", lookback))
+		return 1
+	}
+
+	var err error
+	if ratesFile == "" {
+		if ratesFile, err = project.RatesPath(); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	} else if _, err := os.Stat(ratesFile); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	rates, err := project.LoadRates(ratesFile)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// rounding flags override the rates file
+	cmdFlags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "round-per":
+			rates.Rounding.Per = roundPer
+		case "increment":
+			rates.Rounding.Increment = increment
+		case "minimum":
+			rates.Rounding.Minimum = minimum
+		}
+	})
+	if err := rates.Rounding.Validate(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	userCfg, err := project.LoadUserConfig()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if tz == "" {
+		tz = userCfg.TimeZone
+	}
+	loc, err := util.ParseTimeZone(tz)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	weekday := time.Sunday
+	if userCfg.WeekStart != "" {
+		if weekday, err = util.ParseWeekday(userCfg.WeekStart); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
+	index, err := project.NewIndex()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	tagList := []string{}
+	if tags != "" {
+		tagList = util.Map(strings.Split(tags, ","), strings.TrimSpace)
+	}
+	projects, err := index.Get(tagList, all)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// invoices are for this month unless a date range is specified
+	if !(fromDate != "" || toDate != "" || thisWeek || lastWeek || thisMonth || lastMonth || thisYear || lastYear) {
+		thisMonth = true
+	}
+
+	// set max to absurdly high value for number of possible commits
+	limiter, err := scm.NewCommitLimiter(
+		2147483647, fromDate, toDate, author, "",
+		false, false, thisWeek, lastWeek,
+		thisMonth, lastMonth, thisYear, lastYear, weekday, loc)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	limiter.TimeSpent = timeSpent
+	limiter.Lookback = time.Duration(lookback) * 24 * time.Hour
+
+	projCommits := []report.ProjectCommits{}
+	for _, p := range projects {
+		commits, err := scm.CommitIDs(limiter, p)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		projCommits = append(projCommits, report.ProjectCommits{Path: p, Commits: commits})
+	}
+
+	out, err := report.Invoice(projCommits,
+		report.OutputOptions{
+			TerminalOff: terminalOff,
+			AppOff:      appOff,
+			Color:       color,
+			Location:    loc,
+			Output:      output,
+			DateRange:   limiter.DateRange,
+			TimeSpent:   timeSpent,
+			Rates:       rates})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	c.UI.Output(out)
+
+	return 0
+}
+
+// Synopsis return help for invoice command
+func (c InvoiceCmd) Synopsis() string {
+	return "Display billable time and amounts for git repositories"
+}
@@ -0,0 +1,77 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/util"
+	"github.com/mitchellh/cli"
+)
+
+func TestInvoice(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	// gtm files must only be created in the test repo
+	if err := os.Chdir(repo.Workdir()); err != nil {
+		t.Fatal(err)
+	}
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	ratesPath := filepath.Join(repo.Workdir(), project.GTMDir, project.RatesFile)
+	if err := ioutil.WriteFile(ratesPath, []byte(`{"default": {"hourly": 100, "currency": "EUR"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// time is rounded for each commit when -round-per is not given
+	for _, args := range [][]string{
+		{"-rates", ratesPath, "-round-per", "commit", "-increment", "15", "-output", "csv"},
+		{"-rates", ratesPath, "-increment", "15", "-output", "csv"},
+	} {
+		ui := new(cli.MockUi)
+		c := InvoiceCmd{UI: ui}
+
+		rc := c.Run(args)
+
+		if rc != 0 {
+			t.Errorf("gtm invoice(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+		}
+
+		want := "total,,,,900,0.25,,EUR,25.00"
+		if !strings.Contains(ui.OutputWriter.String(), want) {
+			t.Errorf("gtm invoice(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+		}
+	}
+}
+
+func TestInvoiceInvalidRounding(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := InvoiceCmd{UI: ui}
+
+	args := []string{"-round-per", "week"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm invoice(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Rounding per week is not valid") {
+		t.Errorf("gtm invoice(%+v), want 'Rounding per week is not valid' got %s", args, ui.ErrorWriter.String())
+	}
+}
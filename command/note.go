@@ -0,0 +1,181 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/scm"
+	"github.com/mitchellh/cli"
+)
+
+// NoteVerifyCmd contains methods for the note verify command
+type NoteVerifyCmd struct {
+	UI cli.Ui
+}
+
+// NewNoteVerify returns new NoteVerifyCmd struct
+func NewNoteVerify() (cli.Command, error) {
+	return NoteVerifyCmd{}, nil
+}
+
+// Help returns help for the note verify command
+func (c NoteVerifyCmd) Help() string {
+	helpText := `
+Usage: gtm note verify [options] [<Commit-ID>...]
+
+  Verify the signatures of time data for commits, defaults to the last commit.
+
+  Notes signed with your signing key or a public key listed in the
+  .gtm-trusted-keys file of the git repository are trusted.
+
+  Time data is signed for its commit, time data copied to another commit is invalid. This includes
+  the time data git copies when a signed commit is amended or rebased.
+
+Options:
+
+  -namespace=gtm-data        The git notes namespace to verify
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run executes note verify command with args
+func (c NoteVerifyCmd) Run(args []string) int {
+	var nameSpace string
+	cmdFlags := flag.NewFlagSet("note verify", flag.ContinueOnError)
+	cmdFlags.StringVar(&nameSpace, "namespace", project.NoteNameSpace, "")
+	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	projPath, _, err := project.Paths()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	commits := cmdFlags.Args()
+	if len(commits) == 0 {
+		head, err := scm.HeadCommit(projPath)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		if head.ID == "" {
+			c.UI.Error(scm.ErrHeadUnborn.Error())
+			return 1
+		}
+		commits = []string{head.ID}
+	}
+
+	// SHA-256 ids are accepted so SHA-256 repositories are reported as not supported
+	commitIDRegex := regexp.MustCompile(`\A([0-9a-f]{40}|[0-9a-f]{64})\z`)
+	for _, commitID := range commits {
+		if !commitIDRegex.MatchString(commitID) {
+			c.UI.Error(fmt.Sprintf("\nNot a valid commit id %s\n", commitID))
+			return 1
+		}
+	}
+
+	keyPath, err := project.SigningKeyPath()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	key, err := note.LoadSigningKey(keyPath)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	trusted, err := note.LoadTrustedKeys(filepath.Join(projPath, project.TrustedKeysFile), key)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	rc := 0
+	for _, commitID := range commits {
+		n, err := scm.ReadNote(commitID, nameSpace, false, projPath)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		status, err := note.Verify(n.Note, commitID, trusted)
+		line := fmt.Sprintf("%s %-8s %s", commitID[:7], status, n.Summary)
+		if err != nil {
+			line += fmt.Sprintf(" (%s)", err)
+		}
+		if status == note.SignatureInvalid {
+			rc = 1
+		}
+		c.UI.Output(line)
+	}
+
+	return rc
+}
+
+// Synopsis returns help for the note verify command
+func (c NoteVerifyCmd) Synopsis() string {
+	return "Verify signatures of time data"
+}
+
+// NoteKeygenCmd contains methods for the note keygen command
+type NoteKeygenCmd struct {
+	UI cli.Ui
+}
+
+// NewNoteKeygen returns new NoteKeygenCmd struct
+func NewNoteKeygen() (cli.Command, error) {
+	return NoteKeygenCmd{}, nil
+}
+
+// Help returns help for the note keygen command
+func (c NoteKeygenCmd) Help() string {
+	helpText := `
+Usage: gtm note keygen
+
+  Create an ed25519 key in ~/.git-time-metric for signing time data.
+
+  Once the key exists time data is signed when it is saved with a commit.
+  Add the public key to the .gtm-trusted-keys file of a git repository
+  so others can verify your time data.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run executes note keygen command with args
+func (c NoteKeygenCmd) Run(args []string) int {
+	cmdFlags := flag.NewFlagSet("note keygen", flag.ContinueOnError)
+	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	keyPath, err := project.SigningKeyPath()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	pub, err := note.GenerateSigningKey(keyPath)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Signing key saved to %s\n\nPublic key for %s:\n%s", keyPath, project.TrustedKeysFile, note.FormatPublicKey(pub)))
+	return 0
+}
+
+// Synopsis returns help for the note keygen command
+func (c NoteKeygenCmd) Synopsis() string {
+	return "Create a key for signing time data"
+}
@@ -0,0 +1,136 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/scm"
+	"github.com/git-time-metric/gtm/util"
+	"github.com/mitchellh/cli"
+)
+
+func TestNoteVerifyDefaultOptions(t *testing.T) {
+	home, err := ioutil.TempDir("", "gtm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	saveHome := project.GTMHomeDir
+	defer func() { project.GTMHomeDir = saveHome }()
+	project.GTMHomeDir = home
+
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	// gtm files must only be created in the test repo
+	if err := os.Chdir(repo.Workdir()); err != nil {
+		t.Fatal(err)
+	}
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	verify := func(want string) {
+		ui := new(cli.MockUi)
+		c := NoteVerifyCmd{UI: ui}
+
+		args := []string{}
+		rc := c.Run(args)
+
+		wantRC := 0
+		if want == note.SignatureInvalid {
+			wantRC = 1
+		}
+		if rc != wantRC {
+			t.Errorf("gtm note verify(%+v), want %d got %d, %s", args, wantRC, rc, ui.ErrorWriter.String())
+		}
+		fields := strings.Fields(ui.OutputWriter.String())
+		if len(fields) < 2 || fields[1] != want {
+			t.Errorf("gtm note verify(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+		}
+	}
+
+	// notes are not signed without a signing key
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go")))
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	verify(note.SignatureUnsigned)
+
+	keyPath, err := project.SigningKeyPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := note.GenerateSigningKey(keyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	repo.SaveFile("event_test.go", "event", "")
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event_test.go"))
+	repo.Commit(repo.Stage(filepath.Join("event", "event_test.go")))
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	verify(note.SignatureSigned)
+
+	// a signed note copied to another commit is invalid
+	signed, err := scm.HeadCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := scm.ReadNote(signed.ID, project.NoteNameSpace, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.SaveFile("README", "", "event\n")
+	repo.Commit(repo.Stage("README"))
+	if err := scm.CreateNote(n.Note, project.NoteNameSpace); err != nil {
+		t.Fatal(err)
+	}
+
+	verify(note.SignatureInvalid)
+}
+
+func TestNoteVerifyInvalidCommit(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	repo.Seed()
+	// gtm files must only be created in the test repo
+	if err := os.Chdir(repo.Workdir()); err != nil {
+		t.Fatal(err)
+	}
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	ui := new(cli.MockUi)
+	c := NoteVerifyCmd{UI: ui}
+
+	args := []string{"invalid-sha1"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm note verify(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+}
+
+func TestNoteVerifyInvalidOption(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := NoteVerifyCmd{UI: ui}
+
+	args := []string{"-invalid"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm note verify(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "Usage:") {
+		t.Errorf("gtm note verify(%+v), want 'Usage:'  got %d, %s", args, rc, ui.OutputWriter.String())
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/git-time-metric/gtm/metric"
+	"github.com/git-time-metric/gtm/note"
 	"github.com/git-time-metric/gtm/project"
 	"github.com/git-time-metric/gtm/report"
 	"github.com/git-time-metric/gtm/scm"
@@ -35,19 +37,70 @@ func NewReport() (cli.Command, error) {
 // Help returns help for report command
 func (c ReportCmd) Help() string {
 	helpText := `
-Usage: gtm report [options] <Commit-ID>...
+Usage: gtm report [options] <revision>...
 
   Display reports for one or more git repositories.
 
+  Revisions are anything git rev-parse accepts, i.e. commit ids, abbreviated ids, branches, tags,
+  HEAD~3 and ranges such as main..my-branch or v1.0...v1.1. Revisions can also be read from stdin,
+  i.e. 'git log --format=%H -10 | gtm report'. SHA-256 repositories are not supported.
+
 Options:
 
   Report Formats:
 
-  -format=commits            Specify report format [summary|project|commits|files|timeline-hours|timeline-commits] (default commits)
+  -format=commits            Specify report format [summary|project|commits|files|timeline-hours|timeline-commits|authors|dirs|categories|issues|sessions|focus|timesheet] (default commits)
+                             timesheet defaults to -this-week and shows time on the day it was spent
+                             categories shows time by file category (test, docs, config, build, generated or source) and language,
+                             rules can be added with categories in ~/.git-time-metric/config.json, see the project package's CategoryRules
+                             issues shows time by issue key found in commit messages and branch names, i.e. PROJ-123,
+                             branch names are only used for commits on exactly one local branch, once a branch is merged
+                             its commits are only grouped by the keys in their messages
+                             sessions shows contiguous work sessions by day with the projects and files of each session
+                             and the largest idle gaps between sessions, committed time is recorded by hour so sessions
+                             start and end within the hour
+                             focus shows project switches, file switches per hour, deep work and a focus score by day,
+                             the score is the percentage of time in deep work less 5 points for each project switch,
+                             switches are counted within sessions and by minute for uncommitted time with -include-pending
+                             template:<name> uses the text/template in .gtm-templates/<name>.tmpl in the repository
+                             or in ~/.git-time-metric/templates/<name>.tmpl, see the report package's TemplateData
+  -issue-pattern=""          Regular expression for issue keys in the issues format, the first group is the key if it has one,
+                             defaults to issuePattern in ~/.git-time-metric/config.json or [A-Z][A-Z0-9]+-[0-9]+
+  -gap=15                    Minutes without time spent that end a session in the sessions and focus formats,
+                             committed time is by hour so it is only split at idle hours, the gap applies by minute
+                             to uncommitted time with -include-pending
+  -deep-work=60              Minutes spent in a session of a single project for it to be deep work in the focus format
+  -depth=0                   Number of directory levels for the dirs format, 0 is no limit
+  -by-tag=false              Show a row for each project tag instead of each project in the timesheet format
+  -decimal=false             Show decimal hours in the timesheet format, i.e. 7.25 instead of 7:15
+  -round=0                   Round each day to the nearest minutes in the timesheet format, i.e. -round=15
+  -group-by=""               Group the summary and project formats by period with subtotals [day|week|month|quarter|year]
+  -week-start=sunday         First day of the week for -this-week, -last-week and -group-by=week [sunday|monday|...]
+                             The default can be set with weekStart in ~/.git-time-metric/config.json
+  -output=text               Specify output [text|json|csv|tsv|html|markdown], json, csv and tsv are for scripting
+                             html is a single page with charts and tables for all formats, i.e 'gtm report -output html > report.html'
+                             markdown is for pasting into pull requests, only for the commits, files and summary formats
+  -compare=false             Compare the time spent by project, author and file category to the previous period,
+                             i.e. this week to last week or this month to last month, defaults to -this-week
+  -include-pending=false     Include uncommitted time as an (uncommitted) entry, only for the commits, summary, files,
+                             timeline-hours, project, sessions and focus formats
   -full-message=false        Include full commit message
   -terminal-off=false        Exclude time spent in terminal (Terminal plug-in is required)
   -app-off=false             Exclude time spent in apps
+  -include=""                Only include files matching comma separated globs, i.e. -include './api/,*.go'
+                             a glob without a slash matches file names, a glob ending in / matches a directory
+                             at any depth or only at the root of the repository if it starts with ./
+  -exclude=""                Exclude files matching comma separated globs, i.e. -exclude '*_test.go,vendor/'
+  -file-status=""            Only include files with a status, comma separated [m|r|d] for modified, read or deleted
+  -kind=""                   Only include kinds of files, comma separated [source|terminal|app]
+  -min-duration=""           Only include files with at least this much time spent in a commit, i.e. 30s or 5m
+                             commits without time spent in the included files are not reported
   -force-color=false         Always output color even if no terminal is detected, i.e 'gtm report -color | less -R'
+  -verify=false              Show the signature status of each commit's time data [signed|unsigned|invalid]
+  -no-cache=false            Read notes without the cache in ~/.git-time-metric/cache, the cache is also off if GTM_NO_CACHE is set
+  -tz=local                  Time zone for dates, commit limiting and timelines [local|utc|author|<zone name>, i.e. America/Chicago]
+                             author reports each commit in the time zone it was recorded in
+                             The default can be set with timeZone in ~/.git-time-metric/config.json
   -testing=false             This is used for automated testing to force default test path
 
   Commit Limiting:
@@ -55,7 +108,8 @@ Options:
   -n int=1                   Limit output, 0 is no limits, defaults to 1 when no limiting flags otherwise defaults to 0
   -from-date=yyyy-mm-dd      Show commits starting from this date
   -to-date=yyyy-mm-dd        Show commits thru the end of this date
-  -author=""                 Show commits which contain author substring
+  -author=""                 Show commits which contain author name or email substring
+  -mailmap=""                Author alias file in .mailmap format, each repository's .mailmap is always used
   -message=""                Show commits which contain message substring
   -today=false               Show commits for today
   -yesterday=false           Show commits for yesterday
@@ -65,6 +119,9 @@ Options:
   -last-month=false          Show commits for last month
   -this-year=false           Show commits for this year
   -last-year=false           Show commits for last year
+  -time-spent=false          Report the time spent within the date range instead of the commits made within it,
+                             time spent before or after the date range is excluded
+  -lookback=30               Days before the date range to search for commits with -time-spent, i.e. for long-running branches
 
   Multi-Project Reporting:
 
@@ -76,16 +133,34 @@ Options:
 
 // Run executes report command with args
 func (c ReportCmd) Run(args []string) int {
-	var limit int
-	var color, terminalOff, appOff, fullMessage, testing bool
+	var limit, depth, round, lookback, gap, deepWork int
+	var color, terminalOff, appOff, fullMessage, testing, verify, noCache, byTag, decimal, timeSpent, includePending, compare bool
 	var today, yesterday, thisWeek, lastWeek, thisMonth, lastMonth, thisYear, lastYear, all bool
-	var fromDate, toDate, message, author, tags, format string
+	var fromDate, toDate, message, author, tags, format, tz, output, mailmapFile, weekStart, groupBy string
+	var include, exclude, fileStatus, kind, minDuration, issuePattern string
 	cmdFlags := flag.NewFlagSet("report", flag.ContinueOnError)
 	cmdFlags.BoolVar(&color, "force-color", false, "")
 	cmdFlags.BoolVar(&terminalOff, "terminal-off", false, "")
 	cmdFlags.BoolVar(&appOff, "app-off", false, "")
+	cmdFlags.StringVar(&include, "include", "", "")
+	cmdFlags.StringVar(&exclude, "exclude", "", "")
+	cmdFlags.StringVar(&fileStatus, "file-status", "", "")
+	cmdFlags.StringVar(&kind, "kind", "", "")
+	cmdFlags.StringVar(&minDuration, "min-duration", "", "")
 	cmdFlags.StringVar(&format, "format", "commits", "")
+	cmdFlags.StringVar(&output, "output", report.OutputText, "")
 	cmdFlags.IntVar(&limit, "n", 0, "")
+	cmdFlags.StringVar(&issuePattern, "issue-pattern", "", "")
+	cmdFlags.IntVar(&gap, "gap", report.DefaultSessionGap/60, "")
+	cmdFlags.IntVar(&deepWork, "deep-work", report.DefaultDeepWork/60, "")
+	cmdFlags.IntVar(&depth, "depth", 0, "")
+	cmdFlags.BoolVar(&byTag, "by-tag", false, "")
+	cmdFlags.BoolVar(&decimal, "decimal", false, "")
+	cmdFlags.IntVar(&round, "round", 0, "")
+	cmdFlags.StringVar(&groupBy, "group-by", "", "")
+	cmdFlags.StringVar(&weekStart, "week-start", "", "")
+	cmdFlags.BoolVar(&compare, "compare", false, "")
+	cmdFlags.BoolVar(&includePending, "include-pending", false, "")
 	cmdFlags.BoolVar(&fullMessage, "full-message", false, "")
 	cmdFlags.StringVar(&fromDate, "from-date", "", "")
 	cmdFlags.StringVar(&toDate, "to-date", "", "")
@@ -97,84 +172,200 @@ func (c ReportCmd) Run(args []string) int {
 	cmdFlags.BoolVar(&lastMonth, "last-month", false, "")
 	cmdFlags.BoolVar(&thisYear, "this-year", false, "")
 	cmdFlags.BoolVar(&lastYear, "last-year", false, "")
+	cmdFlags.BoolVar(&timeSpent, "time-spent", false, "")
+	cmdFlags.IntVar(&lookback, "lookback", 30, "")
 	cmdFlags.StringVar(&author, "author", "", "")
+	cmdFlags.StringVar(&mailmapFile, "mailmap", "", "")
 	cmdFlags.StringVar(&message, "message", "", "")
 	cmdFlags.StringVar(&tags, "tags", "", "")
 	cmdFlags.BoolVar(&all, "all", false, "")
 	cmdFlags.BoolVar(&testing, "testing", false, "")
+	cmdFlags.BoolVar(&verify, "verify", false, "")
+	cmdFlags.BoolVar(&noCache, "no-cache", false, "")
+	cmdFlags.StringVar(&tz, "tz", "", "")
 	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
 	}
 
-	if !util.StringInSlice([]string{"summary", "commits", "timeline-hours", "files", "timeline-commits", "project"}, format) {
+	tplName := ""
+	if strings.HasPrefix(format, report.TemplateFormatPrefix) {
+		tplName = strings.TrimPrefix(format, report.TemplateFormatPrefix)
+		format = "template"
+	}
+
+	if !util.StringInSlice([]string{"summary", "commits", "timeline-hours", "files", "timeline-commits", "project", "authors", "dirs", "categories", "issues", "sessions", "focus", "timesheet", "template"}, format) {
 		c.UI.Error(fmt.Sprintf("report --format=%s not valid\n", format))
 		return 1
 	}
 
+	if format == "template" && output != report.OutputText {
+		c.UI.Error(fmt.Sprintf("report --output=%s not valid for --format=%s%s\n", output, report.TemplateFormatPrefix, tplName))
+		return 1
+	}
+
+	if !util.StringInSlice(report.OutputFormats, output) && output != report.OutputHTML && output != report.OutputMarkdown {
+		c.UI.Error(fmt.Sprintf("report --output=%s not valid\n", output))
+		return 1
+	}
+
+	if depth < 0 {
+		c.UI.Error(fmt.Sprintf("report --depth=%d not valid\n", depth))
+		return 1
+	}
+
+	if gap < 1 {
+		c.UI.Error(fmt.Sprintf("report --gap=%d not valid\n", gap))
+		return 1
+	}
+
+	if deepWork < 1 {
+		c.UI.Error(fmt.Sprintf("report --deep-work=%d not valid\n", deepWork))
+		return 1
+	}
+
+	if round < 0 {
+		c.UI.Error(fmt.Sprintf("report --round=%d not valid\n", round))
+		return 1
+	}
+
+	if lookback < 0 {
+		c.UI.Error(fmt.Sprintf("report --lookback=%d not valid\n", lookback))
+		return 1
+	}
+
+	if groupBy != "" && !util.StringInSlice(util.Periods, groupBy) {
+		c.UI.Error(fmt.Sprintf("report --group-by=%s not valid\n", groupBy))
+		return 1
+	}
+
+	if groupBy != "" && (output == report.OutputHTML || !util.StringInSlice([]string{"summary", "project"}, format)) {
+		c.UI.Error(fmt.Sprintf("report --group-by not valid for --format=%s --output=%s\n", format, output))
+		return 1
+	}
+
+	if includePending &&
+		(output == report.OutputHTML ||
+			!util.StringInSlice([]string{"commits", "summary", "files", "timeline-hours", "project", "sessions", "focus"}, format)) {
+		c.UI.Error(fmt.Sprintf("report --include-pending not valid for --format=%s --output=%s\n", format, output))
+		return 1
+	}
+
+	if compare && (format != "commits" || includePending || output == report.OutputHTML || output == report.OutputMarkdown) {
+		c.UI.Error(fmt.Sprintf("report --compare not valid for --format=%s --output=%s\n", format, output))
+		return 1
+	}
+
+	if output == report.OutputMarkdown && !util.StringInSlice(report.MarkdownFormats, format) {
+		c.UI.Error(fmt.Sprintf("report --output=%s not valid for --format=%s\n", output, format))
+		return 1
+	}
+
+	files, err := note.NewFileFilter(include, exclude, fileStatus, kind, minDuration)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	userCfg, err := project.LoadUserConfig()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if tz == "" {
+		tz = userCfg.TimeZone
+	}
+
+	var issueRegex *regexp.Regexp
+	if format == "issues" {
+		if issuePattern == "" {
+			issuePattern = userCfg.IssuePattern
+		}
+		if issuePattern == "" {
+			issuePattern = report.DefaultIssuePattern
+		}
+		if issueRegex, err = regexp.Compile(issuePattern); err != nil {
+			c.UI.Error(fmt.Sprintf("report --issue-pattern=%s not valid, %s\n", issuePattern, err))
+			return 1
+		}
+	}
+
+	// commits are limited using the system's time zone when reporting in the author's time zone
+	authorZone := strings.ToLower(tz) == "author"
+	if authorZone {
+		tz = ""
+	}
+	loc, err := util.ParseTimeZone(tz)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if weekStart == "" {
+		weekStart = userCfg.WeekStart
+	}
+	weekday := time.Sunday
+	if weekStart != "" {
+		if weekday, err = util.ParseWeekday(weekStart); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
+	mailmap := scm.Mailmap{}
+	if mailmapFile != "" {
+		if _, err := os.Stat(mailmapFile); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		if mailmap, err = scm.LoadMailmap(mailmapFile); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
 	var (
 		commits []string
 		out     string
-		err     error
 	)
 
-	const invalidSHA1 = "\nNot a valid commit SHA-1 %s\n"
-
 	// if running from within a MINGW console isatty detection does not work
 	// https://github.com/mintty/mintty/issues/482
 	isMinGW := strings.HasPrefix(os.Getenv("MSYSTEM"), "MINGW")
 
-	sha1Regex := regexp.MustCompile(`\A([0-9a-f]{40})\z`)
-
 	projCommits := []report.ProjectCommits{}
+	dateRange := util.DateRange{}
+	// previousCommits are the commits for compareRange, the period before dateRange, when comparing
+	previousCommits := []report.ProjectCommits{}
+	compareRange := util.DateRange{}
 
 	switch {
+	case compare && !testing && (len(cmdFlags.Args()) > 0 || (!isMinGW && !isatty.IsTerminal(os.Stdin.Fd()))):
+		c.UI.Error("report --compare not valid with revisions\n")
+		return 1
+
 	case !testing && !isMinGW && !isatty.IsTerminal(os.Stdin.Fd()):
+		revs := []string{}
 		scanner := bufio.NewScanner(os.Stdin)
 		for scanner.Scan() {
-			if !sha1Regex.MatchString(scanner.Text()) {
-				c.UI.Error(fmt.Sprintf("%s %s", invalidSHA1, scanner.Text()))
-				return 1
+			if rev := strings.TrimSpace(scanner.Text()); rev != "" {
+				revs = append(revs, rev)
 			}
-			commits = append(commits, scanner.Text())
-		}
-		curProjPath, err := scm.GitRepoPath()
-		if err != nil {
-			c.UI.Error(err.Error())
-			return 1
 		}
-		curProjPath, err = scm.Workdir(curProjPath)
+		pc, err := projectRevisions(revs)
 		if err != nil {
 			c.UI.Error(err.Error())
 			return 1
 		}
-
-		projCommits = append(projCommits, report.ProjectCommits{Path: curProjPath, Commits: commits})
+		projCommits = append(projCommits, pc)
 
 	case !testing && len(cmdFlags.Args()) > 0:
-		for _, a := range cmdFlags.Args() {
-			if !sha1Regex.MatchString(a) {
-				c.UI.Error(fmt.Sprintf("%s %s", invalidSHA1, a))
-				return 1
-			}
-			commits = append(commits, a)
-		}
-		curProjPath, err := scm.GitRepoPath()
+		pc, err := projectRevisions(cmdFlags.Args())
 		if err != nil {
 			c.UI.Error(err.Error())
 			return 1
 		}
-		curProjPath, err = scm.Workdir(curProjPath)
-		if err != nil {
-			c.UI.Error(err.Error())
-			return 1
-		}
-		if err != nil {
-			c.UI.Error(err.Error())
-			return 1
-		}
-
-		projCommits = append(projCommits, report.ProjectCommits{Path: curProjPath, Commits: commits})
+		projCommits = append(projCommits, pc)
 
 	default:
 		index, err := project.NewIndex()
@@ -193,8 +384,16 @@ func (c ReportCmd) Run(args []string) int {
 			return 1
 		}
 
-		// hack, if project format we want all commits for the project
-		if format == "project" && limit == 0 {
+		// timesheets and comparisons cover a week unless a date range is specified
+		if (format == "timesheet" || compare) &&
+			!(fromDate != "" || toDate != "" || today || yesterday || thisWeek || lastWeek ||
+				thisMonth || lastMonth || thisYear || lastYear) {
+			thisWeek = true
+		}
+
+		// hack, if project, authors, issues, sessions, focus, timesheet or compare format we want all commits for the project
+		if (format == "project" || format == "authors" || format == "issues" || format == "sessions" ||
+			format == "focus" || format == "timesheet" || compare) && limit == 0 {
 			// set max to absurdly high value for number of possible commits
 			limit = 2147483647
 		}
@@ -202,12 +401,32 @@ func (c ReportCmd) Run(args []string) int {
 		limiter, err := scm.NewCommitLimiter(
 			limit, fromDate, toDate, author, message,
 			today, yesterday, thisWeek, lastWeek,
-			thisMonth, lastMonth, thisYear, lastYear)
+			thisMonth, lastMonth, thisYear, lastYear, weekday, loc)
 
 		if err != nil {
 			c.UI.Error(err.Error())
 			return 1
 		}
+		limiter.Mailmap = mailmap
+		limiter.TimeSpent = timeSpent
+		limiter.Lookback = time.Duration(lookback) * 24 * time.Hour
+
+		if compare {
+			if limiter.DateRange.Start.IsZero() {
+				c.UI.Error("report --compare requires a date range with a starting date\n")
+				return 1
+			}
+			if limiter.DateRange.End.IsZero() {
+				limiter.DateRange.End = util.TodayRange(loc).End
+			}
+			compareRange = limiter.DateRange.Previous()
+		}
+
+		dateRange = limiter.DateRange
+		if format == "timesheet" {
+			// time spent within the date range can be committed after the range ends
+			limiter.DateRange.End = time.Time{}
+		}
 
 		limit = limiter.Max
 
@@ -218,32 +437,121 @@ func (c ReportCmd) Run(args []string) int {
 				return 1
 			}
 			projCommits = append(projCommits, report.ProjectCommits{Path: p, Commits: commits})
+
+			if compare {
+				previousLimiter := limiter
+				previousLimiter.DateRange = compareRange
+				if commits, err = scm.CommitIDs(previousLimiter, p); err != nil {
+					c.UI.Error(err.Error())
+					return 1
+				}
+				previousCommits = append(previousCommits, report.ProjectCommits{Path: p, Commits: commits})
+			}
+		}
+	}
+
+	if includePending {
+		// sessions and focus use the finest timelines available, uncommitted time is by minute
+		byMinute := format == "sessions" || format == "focus"
+		for i := range projCommits {
+			if byMinute {
+				projCommits[i].Pending, err = metric.ProcessByMinute(projCommits[i].Path)
+			} else {
+				projCommits[i].Pending, err = metric.Process(true, projCommits[i].Path)
+			}
+			if err != nil {
+				c.UI.Error(err.Error())
+				return 1
+			}
+			projCommits[i].PendingByMinute = byMinute
 		}
 	}
 
 	options := report.OutputOptions{
-		FullMessage: fullMessage,
-		TerminalOff: terminalOff,
-		AppOff:      appOff,
-		Color:       color,
-		Limit:       limit}
+		FullMessage:  fullMessage,
+		TerminalOff:  terminalOff,
+		AppOff:       appOff,
+		Color:        color,
+		Limit:        limit,
+		Verify:       verify,
+		NoCache:      noCache,
+		Warn:         c.UI.Warn,
+		Location:     loc,
+		AuthorZone:   authorZone,
+		Output:       output,
+		Mailmap:      mailmap,
+		Depth:        depth,
+		DateRange:    dateRange,
+		TimeSpent:    timeSpent,
+		ByTag:        byTag,
+		Decimal:      decimal,
+		Round:        round,
+		GroupBy:      groupBy,
+		WeekStart:    weekday,
+		Categories:   userCfg.Categories,
+		CompareRange: compareRange,
+		Files:        files,
+		IssuePattern: issueRegex,
+		SessionGap:   gap * 60,
+		DeepWork:     deepWork * 60}
+
+	tplPath := ""
+	if format == "template" {
+		// templates in the current repository take precedence
+		repoPath := ""
+		if p, err := scm.GitRepoPath(); err == nil {
+			repoPath, _ = scm.Workdir(p)
+		}
+		tplDirs, err := project.TemplateDirs(repoPath)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		if tplPath, err = report.FindTemplate(tplName, tplDirs...); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
 
+	// the spinner would corrupt output meant for scripts
 	s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	s.Start()
+	if output == report.OutputText {
+		s.Start()
+	}
 
-	switch format {
-	case "project":
+	switch {
+	case output == report.OutputHTML:
+		out, err = report.HTML(projCommits, options)
+	case compare:
+		out, err = report.Compare(projCommits, previousCommits, options)
+	case format == "project":
 		out, err = report.ProjectSummary(projCommits, options)
-	case "summary":
+	case format == "summary":
 		out, err = report.CommitSummary(projCommits, options)
-	case "commits":
+	case format == "commits":
 		out, err = report.Commits(projCommits, options)
-	case "files":
+	case format == "files":
 		out, err = report.Files(projCommits, options)
-	case "timeline-hours":
+	case format == "timeline-hours":
 		out, err = report.Timeline(projCommits, options)
-	case "timeline-commits":
+	case format == "timeline-commits":
 		out, err = report.TimelineCommits(projCommits, options)
+	case format == "authors":
+		out, err = report.Authors(projCommits, options)
+	case format == "dirs":
+		out, err = report.Dirs(projCommits, options)
+	case format == "categories":
+		out, err = report.Categories(projCommits, options)
+	case format == "issues":
+		out, err = report.Issues(projCommits, options)
+	case format == "sessions":
+		out, err = report.Sessions(projCommits, options)
+	case format == "focus":
+		out, err = report.Focus(projCommits, options)
+	case format == "timesheet":
+		out, err = report.Timesheet(projCommits, options)
+	case format == "template":
+		out, err = report.UserTemplate(projCommits, options, tplPath)
 	}
 
 	s.Stop()
@@ -257,6 +565,23 @@ func (c ReportCmd) Run(args []string) int {
 	return 0
 }
 
+// projectRevisions returns the current project's commits for revisions such as HEAD~3 or main..feature
+func projectRevisions(revs []string) (report.ProjectCommits, error) {
+	projPath, err := scm.GitRepoPath()
+	if err != nil {
+		return report.ProjectCommits{}, err
+	}
+	projPath, err = scm.Workdir(projPath)
+	if err != nil {
+		return report.ProjectCommits{}, err
+	}
+	commits, err := scm.RevParse(revs, projPath)
+	if err != nil {
+		return report.ProjectCommits{}, err
+	}
+	return report.ProjectCommits{Path: projPath, Commits: commits}, nil
+}
+
 // Synopsis return help for report command
 func (c ReportCmd) Synopsis() string {
 	return "Display reports for git repositories"
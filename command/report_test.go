@@ -10,11 +10,18 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/git-time-metric/gtm/cache"
 	"github.com/git-time-metric/gtm/project"
 	"github.com/git-time-metric/gtm/util"
 	"github.com/mitchellh/cli"
 )
 
+func TestMain(m *testing.M) {
+	// tests must not write to the user's note cache
+	os.Setenv(cache.DisableEnv, "1")
+	os.Exit(m.Run())
+}
+
 func TestReportDefaultOptions(t *testing.T) {
 	repo := util.NewTestRepo(t, false)
 	defer repo.Remove()
@@ -256,6 +263,114 @@ func TestReportFiles(t *testing.T) {
 	}
 }
 
+func TestReportAuthors(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("event_test.go", "event", "")
+	repo.SaveFile(".mailmap", "", "Random Hacker <random@hacker.com>\n")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496811.event", project.GTMDir, filepath.Join("event", "event_test.go"))
+	repo.SaveFile("1458496818.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go"), filepath.Join("event", "event_test.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-format", "authors", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm report(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+
+	for _, want := range []string{"Random Hacker <random@hacker.com>", "1 commit,", "event/event.go"} {
+		if !strings.Contains(ui.OutputWriter.String(), want) {
+			t.Errorf("gtm report(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+		}
+	}
+}
+
+func TestReportDirs(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("event_test.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496811.event", project.GTMDir, filepath.Join("event", "event_test.go"))
+	repo.SaveFile("1458496818.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go"), filepath.Join("event", "event_test.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-format", "dirs", "-depth", "1", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm report(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+
+	want := "3m  0s 100%         3m  0s/commit  event/"
+	if !strings.Contains(ui.OutputWriter.String(), want) {
+		t.Errorf("gtm report(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+}
+
+func TestReportCategories(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("event_test.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496811.event", project.GTMDir, filepath.Join("event", "event_test.go"))
+	repo.SaveFile("1458496818.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go"), filepath.Join("event", "event_test.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-format", "categories", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm report(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+
+	for _, want := range []string{"commits  source", "commits  test", "100%     1 commits  Go"} {
+		if !strings.Contains(ui.OutputWriter.String(), want) {
+			t.Errorf("gtm report(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+		}
+	}
+}
+
 func TestReportAppsOff(t *testing.T) {
 	repo := util.NewTestRepo(t, false)
 	defer repo.Remove()
@@ -299,6 +414,88 @@ func TestReportAppsOff(t *testing.T) {
 	}
 }
 
+func TestReportOutputJSON(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("event_test.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496811.event", project.GTMDir, filepath.Join("event", "event_test.go"))
+	repo.SaveFile("1458496818.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go"), filepath.Join("event", "event_test.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-output", "json", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm report(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+
+	want := `"file": "event/event.go",`
+	if !strings.Contains(ui.OutputWriter.String(), want) {
+		t.Errorf("gtm report(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	c = ReportCmd{UI: ui}
+
+	args = []string{"-output", "xml", "-testing=true"}
+	rc = c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm report(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+}
+
+func TestReportIncludePending(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-include-pending", "-format", "summary", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm report(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+
+	want := "(uncommitted)"
+	if !strings.Contains(ui.OutputWriter.String(), want) {
+		t.Errorf("gtm report(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	c = ReportCmd{UI: ui}
+
+	args = []string{"-include-pending", "-format", "authors", "-testing=true"}
+	rc = c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm report(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+}
+
 func TestReportInvalidOption(t *testing.T) {
 	ui := new(cli.MockUi)
 	c := ReportCmd{UI: ui}
@@ -313,3 +510,188 @@ func TestReportInvalidOption(t *testing.T) {
 		t.Errorf("gtm report(%+v), want 'Usage:'  got %d, %s", args, rc, ui.OutputWriter.String())
 	}
 }
+
+func TestReportMarkdownInvalidFormat(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-output", "markdown", "-format", "timeline-hours", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm report(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "not valid for --format=timeline-hours") {
+		t.Errorf("gtm report(%+v), want 'not valid for --format=timeline-hours' got %s", args, ui.ErrorWriter.String())
+	}
+}
+
+func TestReportCompareInvalidFormat(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-compare", "-format", "files", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm report(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "report --compare not valid for --format=files") {
+		t.Errorf("gtm report(%+v), want 'report --compare not valid for --format=files' got %s", args, ui.ErrorWriter.String())
+	}
+}
+
+func TestReportInvalidFileFilter(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-kind", "editor", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm report(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "File kind editor not valid") {
+		t.Errorf("gtm report(%+v), want 'File kind editor not valid' got %s", args, ui.ErrorWriter.String())
+	}
+}
+
+func TestReportIssues(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496818.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	// test commits have the message 'This is a commit'
+	args := []string{"-format", "issues", "-issue-pattern", `(This) is`, "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm report(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+	if want := "100%     1 commits  This"; !strings.Contains(ui.OutputWriter.String(), want) {
+		t.Errorf("gtm report(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+}
+
+func TestReportInvalidIssuePattern(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-format", "issues", "-issue-pattern", "([A-Z]", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm report(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "report --issue-pattern=([A-Z] not valid") {
+		t.Errorf("gtm report(%+v), want 'report --issue-pattern=([A-Z] not valid' got %s", args, ui.ErrorWriter.String())
+	}
+}
+
+func TestReportSessions(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496818.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-format", "sessions", "-output", "csv", "-gap", "30", "-tz", "utc", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm report(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+	if want := "session,2016-03-20,2016-03-20T18:00:00Z"; !strings.Contains(ui.OutputWriter.String(), want) {
+		t.Errorf("gtm report(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+}
+
+func TestReportInvalidGap(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-format", "sessions", "-gap", "0", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm report(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "report --gap=0 not valid") {
+		t.Errorf("gtm report(%+v), want 'report --gap=0 not valid' got %s", args, ui.ErrorWriter.String())
+	}
+}
+
+func TestReportFocus(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("event_test.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496818.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	repo.Commit(repo.Stage(filepath.Join("event", "event.go")))
+
+	// save notes to git repository
+	(CommitCmd{UI: new(cli.MockUi)}).Run([]string{"-yes"})
+
+	// uncommitted time by minute
+	repo.SaveFile("1458496863.event", project.GTMDir, filepath.Join("event", "event_test.go"))
+
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-format", "focus", "-output", "csv", "-deep-work", "1", "-include-pending", "-tz", "utc", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm report(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+	if want := "2016-03-20,"; !strings.Contains(ui.OutputWriter.String(), want) {
+		t.Errorf("gtm report(%+v), want %s got %s, %s", args, want, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+}
+
+func TestReportInvalidDeepWork(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := ReportCmd{UI: ui}
+
+	args := []string{"-format", "focus", "-deep-work", "0", "-testing=true"}
+	rc := c.Run(args)
+
+	if rc != 1 {
+		t.Errorf("gtm report(%+v), want 1 got %d, %s", args, rc, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "report --deep-work=0 not valid") {
+		t.Errorf("gtm report(%+v), want 'report --deep-work=0 not valid' got %s", args, ui.ErrorWriter.String())
+	}
+}
@@ -40,6 +40,16 @@ Options:
 
   -app-off=false             Exclude time spent in apps
 
+  -include=""                Only include files matching comma separated globs, i.e. -include './api/,*.go'
+
+  -exclude=""                Exclude files matching comma separated globs, i.e. -exclude '*_test.go,vendor/'
+
+  -file-status=""            Only include files with a status, comma separated [m|r|d] for modified, read or deleted
+
+  -kind=""                   Only include kinds of files, comma separated [source|terminal|app]
+
+  -min-duration=""           Only include files with at least this much time spent, i.e. 30s or 5m
+
   -color=false               Always output color even if no terminal is detected, i.e 'gtm status -color | less -R'
 
   -total-only=false          Only display total pending time
@@ -49,6 +59,8 @@ Options:
   -tags=""                   Project tags to report status for, i.e --tags tag1,tag2
 
   -all=false                 Show status for all projects
+
+  -output=text               Specify output [text|json|csv|tsv], json, csv and tsv are for scripting
 `
 	return strings.TrimSpace(helpText)
 }
@@ -56,16 +68,22 @@ Options:
 // Run executes status command with args
 func (c StatusCmd) Run(args []string) int {
 	var color, terminalOff, appOff, totalOnly, all, profile, longDuration bool
-	var tags string
+	var tags, output, include, exclude, fileStatus, kind, minDuration string
 	cmdFlags := flag.NewFlagSet("status", flag.ContinueOnError)
 	cmdFlags.BoolVar(&color, "color", false, "Always output color even if no terminal is detected. Use this with pagers i.e 'less -R' or 'more -R'")
 	cmdFlags.BoolVar(&terminalOff, "terminal-off", false, "Exclude time spent in terminal (Terminal plugin is required)")
 	cmdFlags.BoolVar(&appOff, "app-off", false, "Exclude time spent in apps")
+	cmdFlags.StringVar(&include, "include", "", "Only include files matching globs")
+	cmdFlags.StringVar(&exclude, "exclude", "", "Exclude files matching globs")
+	cmdFlags.StringVar(&fileStatus, "file-status", "", "Only include files with a status")
+	cmdFlags.StringVar(&kind, "kind", "", "Only include kinds of files")
+	cmdFlags.StringVar(&minDuration, "min-duration", "", "Only include files with at least this much time spent")
 	cmdFlags.BoolVar(&totalOnly, "total-only", false, "Only display total time")
 	cmdFlags.BoolVar(&longDuration, "long-duration", false, "Display total time in long duration format")
 	cmdFlags.StringVar(&tags, "tags", "", "Project tags to show status on")
 	cmdFlags.BoolVar(&all, "all", false, "Show status for all projects")
 	cmdFlags.BoolVar(&profile, "profile", false, "Enable profiling")
+	cmdFlags.StringVar(&output, "output", report.OutputText, "Output format")
 	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
@@ -76,8 +94,23 @@ func (c StatusCmd) Run(args []string) int {
 		return 1
 	}
 
+	if !util.StringInSlice(report.OutputFormats, output) {
+		c.UI.Error(fmt.Sprintf("\nstatus --output=%s not valid\n", output))
+		return 1
+	}
+
+	if totalOnly && output != report.OutputText {
+		c.UI.Error("\n-output option not allowed with -total-only\n")
+		return 1
+	}
+
+	files, err := note.NewFileFilter(include, exclude, fileStatus, kind, minDuration)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	var (
-		err        error
 		commitNote note.CommitNote
 		out        string
 	)
@@ -104,13 +137,25 @@ func (c StatusCmd) Run(args []string) int {
 		LongDuration: longDuration,
 		TerminalOff:  terminalOff,
 		AppOff:       appOff,
-		Color:        color}
+		Color:        color,
+		Output:       output,
+		Files:        files}
 
+	statuses := []report.StatusData{}
 	for _, projPath := range projects {
 		if commitNote, err = metric.Process(true, projPath); err != nil {
 			c.UI.Error(err.Error())
 			return 1
 		}
+		if output != report.OutputText {
+			d, err := report.NewStatusData(commitNote, options, projPath)
+			if err != nil {
+				c.UI.Error(err.Error())
+				return 1
+			}
+			statuses = append(statuses, d)
+			continue
+		}
 		o, err := report.Status(commitNote, options, projPath)
 		if err != nil {
 			c.UI.Error(err.Error())
@@ -119,6 +164,13 @@ func (c StatusCmd) Run(args []string) int {
 		out += o
 	}
 
+	if output != report.OutputText {
+		if out, err = report.StatusOutput(statuses, options); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
 	if totalOnly {
 		// plain output, no ansi escape sequences
 		fmt.Print(out)
@@ -90,6 +90,63 @@ func TestStatusAppOff(t *testing.T) {
 	}
 }
 
+func TestStatusFileFilter(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	repo.Seed()
+	os.Chdir(repo.Workdir())
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("event_test.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458497804.event", project.GTMDir, filepath.Join("event", "event_test.go"))
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	ui := new(cli.MockUi)
+	c := StatusCmd{UI: ui}
+
+	args := []string{"-include", "./event/", "-exclude", "*_test.go"}
+	rc := c.Run(args)
+	if rc != 0 {
+		t.Errorf("gtm status(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "event.go") || strings.Contains(ui.OutputWriter.String(), "event_test.go") {
+		t.Errorf("gtm status(%+v), want 'event.go' and not 'event_test.go' got %s", args, ui.OutputWriter.String())
+	}
+}
+
+func TestStatusOutputCSV(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	repo.Seed()
+	os.Chdir(repo.Workdir())
+
+	(InitCmd{UI: new(cli.MockUi)}).Run([]string{})
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	ui := new(cli.MockUi)
+	c := StatusCmd{UI: ui}
+
+	args := []string{"-output", "csv"}
+	rc := c.Run(args)
+
+	if rc != 0 {
+		t.Errorf("gtm status(%+v), want 0 got %d, %s", args, rc, ui.ErrorWriter.String())
+	}
+
+	want := "project,tags,file,type,status,seconds"
+	if !strings.HasPrefix(ui.OutputWriter.String(), want) {
+		t.Errorf("gtm status(%+v), want %s got %s", args, want, ui.OutputWriter.String())
+	}
+	want = ",event/event.go,file,"
+	if !strings.Contains(ui.OutputWriter.String(), want) {
+		t.Errorf("gtm status(%+v), want %s got %s", args, want, ui.OutputWriter.String())
+	}
+}
+
 func TestStatusInvalidOption(t *testing.T) {
 	ui := new(cli.MockUi)
 	c := StatusCmd{UI: ui}
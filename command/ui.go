@@ -0,0 +1,277 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/git-time-metric/gtm/metric"
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/report"
+	"github.com/git-time-metric/gtm/scm"
+	"github.com/git-time-metric/gtm/ui"
+	"github.com/git-time-metric/gtm/util"
+	isatty "github.com/mattn/go-isatty"
+	"github.com/mitchellh/cli"
+)
+
+// UICmd contains methods for ui command
+type UICmd struct {
+	UI cli.Ui
+}
+
+// NewUI returns new UICmd struct
+func NewUI() (cli.Command, error) {
+	return UICmd{}, nil
+}
+
+// Help returns help for ui command
+func (c UICmd) Help() string {
+	helpText := `
+Usage: gtm ui [options]
+
+  Browse projects, commits and reports in an interactive terminal interface.
+
+  Select a project to see its commits and a commit to see its files and timeline.
+  Reports use the report formats of 'gtm report' and can be switched while browsing,
+  along with the date range and project tags. Press ? for the keys.
+
+Options:
+
+  -tags=""                   Project tags to browse, i.e --tags tag1,tag2, defaults to all projects
+  -terminal-off=false        Exclude time spent in terminal (Terminal plug-in is required)
+  -app-off=false             Exclude time spent in apps
+  -tz=local                  Time zone for dates, commit limiting and timelines [local|utc|<zone name>, i.e. America/Chicago]
+                             The default can be set with timeZone in ~/.git-time-metric/config.json
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run executes ui command with args
+func (c UICmd) Run(args []string) int {
+	var terminalOff, appOff bool
+	var tags, tz string
+	cmdFlags := flag.NewFlagSet("ui", flag.ContinueOnError)
+	cmdFlags.StringVar(&tags, "tags", "", "")
+	cmdFlags.BoolVar(&terminalOff, "terminal-off", false, "")
+	cmdFlags.BoolVar(&appOff, "app-off", false, "")
+	cmdFlags.StringVar(&tz, "tz", "", "")
+	cmdFlags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		c.UI.Error("gtm ui requires a terminal, use gtm report for scripting\n")
+		return 1
+	}
+
+	userCfg, err := project.LoadUserConfig()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if tz == "" {
+		tz = userCfg.TimeZone
+	}
+	loc, err := util.ParseTimeZone(tz)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	weekday := time.Sunday
+	if userCfg.WeekStart != "" {
+		if weekday, err = util.ParseWeekday(userCfg.WeekStart); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
+	issuePattern := userCfg.IssuePattern
+	if issuePattern == "" {
+		issuePattern = report.DefaultIssuePattern
+	}
+	issueRegex, err := regexp.Compile(issuePattern)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("issuePattern %s not valid, %s\n", issuePattern, err))
+		return 1
+	}
+
+	tagList := []string{}
+	if tags != "" {
+		tagList = util.Map(strings.Split(tags, ","), strings.TrimSpace)
+	}
+
+	src := uiSource{
+		options: report.OutputOptions{
+			TerminalOff: terminalOff,
+			AppOff:      appOff,
+			Color:       true,
+			Location:    loc,
+			WeekStart:   weekday,
+			Categories:  userCfg.Categories,
+		},
+		issuePattern: issueRegex,
+	}
+	m, err := ui.NewModel(src, tagList)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if err := ui.Run(m, os.Stdin, os.Stdout); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	return 0
+}
+
+// Synopsis return help for ui command
+func (c UICmd) Synopsis() string {
+	return "Browse projects, commits and reports interactively"
+}
+
+// uiSource provides time data to the UI with the project index and reports
+type uiSource struct {
+	options      report.OutputOptions
+	issuePattern *regexp.Regexp
+}
+
+// Projects returns the indexed projects with any of the tags, all projects if there are no tags
+func (s uiSource) Projects(tags []string) ([]string, error) {
+	index, err := project.NewIndex()
+	if err != nil {
+		return []string{}, err
+	}
+	return index.Get(tags, len(tags) == 0)
+}
+
+// limiter returns a commit limiter for one of the UI's date ranges
+func (s uiSource) limiter(dateRange string) (scm.CommitLimiter, error) {
+	if !util.StringInSlice(ui.DateRanges, dateRange) {
+		return scm.CommitLimiter{}, fmt.Errorf("Date range %s not valid", dateRange)
+	}
+	// set max to absurdly high value for number of possible commits
+	return scm.NewCommitLimiter(
+		2147483647, "", "", "", "",
+		dateRange == "today", dateRange == "yesterday", dateRange == "this-week", dateRange == "last-week",
+		dateRange == "this-month", dateRange == "last-month", dateRange == "this-year", dateRange == "last-year",
+		s.options.WeekStart, s.options.Location)
+}
+
+// projectCommits returns the commits for the projects within the date range
+func (s uiSource) projectCommits(projPaths []string, dateRange, format string) ([]report.ProjectCommits, report.OutputOptions, error) {
+	limiter, err := s.limiter(dateRange)
+	if err != nil {
+		return nil, s.options, err
+	}
+
+	options := s.options
+	options.DateRange = limiter.DateRange
+	options.Limit = limiter.Max
+	if format == "timesheet" {
+		if !limiter.DateRange.IsSet() {
+			return nil, options, fmt.Errorf("The timesheet format requires a date range other than all")
+		}
+		// time spent within the date range can be committed after the range ends
+		limiter.DateRange.End = time.Time{}
+	}
+
+	projCommits := []report.ProjectCommits{}
+	for _, p := range projPaths {
+		commits, err := scm.CommitIDs(limiter, p)
+		if err != nil {
+			return nil, options, err
+		}
+		projCommits = append(projCommits, report.ProjectCommits{Path: p, Commits: commits})
+	}
+	return projCommits, options, nil
+}
+
+// Commits returns a project's commits within the date range
+func (s uiSource) Commits(projPath, dateRange string) ([]report.CommitData, error) {
+	projCommits, options, err := s.projectCommits([]string{projPath}, dateRange, "commits")
+	if err != nil {
+		return nil, err
+	}
+	return report.CommitList(projCommits, options)
+}
+
+// Commit returns the files and timeline for a commit
+func (s uiSource) Commit(projPath, commitID string) (string, error) {
+	projCommits := []report.ProjectCommits{{Path: projPath, Commits: []string{commitID}}}
+	options := s.options
+	options.FullMessage = true
+
+	commits, err := report.Commits(projCommits, options)
+	if err != nil {
+		return "", err
+	}
+	timeline, err := report.Timeline(projCommits, options)
+	if err != nil {
+		return "", err
+	}
+	return commits + "\n" + timeline, nil
+}
+
+// Report returns a report for the projects within the date range
+func (s uiSource) Report(format string, projPaths []string, dateRange string) (string, error) {
+	projCommits, options, err := s.projectCommits(projPaths, dateRange, format)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case "project":
+		return report.ProjectSummary(projCommits, options)
+	case "summary":
+		return report.CommitSummary(projCommits, options)
+	case "commits":
+		return report.Commits(projCommits, options)
+	case "files":
+		return report.Files(projCommits, options)
+	case "timeline-hours":
+		return report.Timeline(projCommits, options)
+	case "timeline-commits":
+		return report.TimelineCommits(projCommits, options)
+	case "authors":
+		return report.Authors(projCommits, options)
+	case "dirs":
+		return report.Dirs(projCommits, options)
+	case "categories":
+		return report.Categories(projCommits, options)
+	case "issues":
+		options.IssuePattern = s.issuePattern
+		return report.Issues(projCommits, options)
+	case "sessions":
+		return report.Sessions(projCommits, options)
+	case "focus":
+		return report.Focus(projCommits, options)
+	case "timesheet":
+		return report.Timesheet(projCommits, options)
+	}
+	return "", fmt.Errorf("Report format %s not valid", format)
+}
+
+// Status returns the pending time for the projects
+func (s uiSource) Status(projPaths []string) (string, error) {
+	out := ""
+	for _, p := range projPaths {
+		commitNote, err := metric.Process(true, p)
+		if err != nil {
+			return "", err
+		}
+		o, err := report.Status(commitNote, s.options, p)
+		if err != nil {
+			return "", err
+		}
+		out += o
+	}
+	return out, nil
+}
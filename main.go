@@ -43,6 +43,21 @@ func main() {
 				UI: ui,
 			}, nil
 		},
+		"ui": func() (cli.Command, error) {
+			return &command.UICmd{
+				UI: ui,
+			}, nil
+		},
+		"invoice": func() (cli.Command, error) {
+			return &command.InvoiceCmd{
+				UI: ui,
+			}, nil
+		},
+		"export": func() (cli.Command, error) {
+			return &command.ExportCmd{
+				UI: ui,
+			}, nil
+		},
 		"status": func() (cli.Command, error) {
 			return &command.StatusCmd{
 				UI: ui,
@@ -64,6 +79,26 @@ func main() {
 				UI: ui,
 			}, nil
 		},
+		"note verify": func() (cli.Command, error) {
+			return &command.NoteVerifyCmd{
+				UI: ui,
+			}, nil
+		},
+		"note keygen": func() (cli.Command, error) {
+			return &command.NoteKeygenCmd{
+				UI: ui,
+			}, nil
+		},
+		"cache clear": func() (cli.Command, error) {
+			return &command.CacheClearCmd{
+				UI: ui,
+			}, nil
+		},
+		"cache stats": func() (cli.Command, error) {
+			return &command.CacheStatsCmd{
+				UI: ui,
+			}, nil
+		},
 	}
 
 	exitStatus, err := c.Run()
@@ -5,6 +5,8 @@
 package metric
 
 import (
+	"path/filepath"
+
 	"github.com/git-time-metric/gtm/event"
 	"github.com/git-time-metric/gtm/note"
 	"github.com/git-time-metric/gtm/project"
@@ -15,6 +17,17 @@ import (
 // Process events for last git commit and save time spent as a git note
 // If interim is true, process events for the current working and staged files
 func Process(interim bool, projPath ...string) (note.CommitNote, error) {
+	return process(interim, true, projPath...)
+}
+
+// ProcessByMinute returns the time spent on the current working and staged files like Process(true),
+// the timelines are by minute instead of by hour
+func ProcessByMinute(projPath ...string) (note.CommitNote, error) {
+	return process(true, false, projPath...)
+}
+
+// process events, timelines are downsampled to hours if downsample is true
+func process(interim, downsample bool, projPath ...string) (note.CommitNote, error) {
 	defer util.Profile()()
 
 	rootPath, gtmPath, err := project.Paths(projPath...)
@@ -50,7 +63,7 @@ func Process(interim bool, projPath ...string) (note.CommitNote, error) {
 			return note.CommitNote{}, err
 		}
 
-		commitNote, err = buildCommitNote(rootPath, commitMap, readonlyMap)
+		commitNote, err = buildCommitNote(rootPath, commitMap, readonlyMap, downsample)
 		if err != nil {
 			return note.CommitNote{}, err
 		}
@@ -61,14 +74,66 @@ func Process(interim bool, projPath ...string) (note.CommitNote, error) {
 			return note.CommitNote{}, err
 		}
 
-		commitNote, err = buildCommitNote(rootPath, commitMap, readonlyMap)
+		commitNote, err = buildCommitNote(rootPath, commitMap, readonlyMap, downsample)
 		if err != nil {
 			return note.CommitNote{}, err
 		}
 
-		if err := scm.CreateNote(note.Marshal(commitNote), project.NoteNameSpace); err != nil {
+		cfg, err := project.LoadConfig(gtmPath)
+		if err != nil {
 			return note.CommitNote{}, err
 		}
+
+		userCfg, err := project.LoadUserConfig()
+		if err != nil {
+			return note.CommitNote{}, err
+		}
+		if userCfg.RecordTimeZone {
+			commitNote.Zone = util.Now().Format("-0700")
+		}
+
+		var secret []byte
+		if cfg.Privacy == note.PrivacyHashed {
+			if secret, err = note.LoadPrivacySecret(filepath.Join(gtmPath, project.PrivacySecretFile)); err != nil {
+				return note.CommitNote{}, err
+			}
+		}
+		sharedNote, err := commitNote.Redact(cfg.Privacy, secret)
+		if err != nil {
+			return note.CommitNote{}, err
+		}
+
+		// notes are signed when the user has a signing key
+		keyPath, err := project.SigningKeyPath()
+		if err != nil {
+			return note.CommitNote{}, err
+		}
+		key, err := note.LoadSigningKey(keyPath)
+		if err != nil {
+			return note.CommitNote{}, err
+		}
+		var head scm.Commit
+		if key != nil {
+			// signatures are for the commit so they can't be copied to other commits
+			if head, err = scm.HeadCommit(); err != nil {
+				return note.CommitNote{}, err
+			}
+		}
+		marshal := func(n note.CommitNote) string {
+			if key == nil {
+				return note.Marshal(n)
+			}
+			return note.Sign(note.Marshal(n), head.ID, key)
+		}
+
+		if err := scm.CreateNote(marshal(sharedNote), project.NoteNameSpace); err != nil {
+			return note.CommitNote{}, err
+		}
+		if cfg.KeepLocal && cfg.Privacy != "" && cfg.Privacy != note.PrivacyFull {
+			if err := scm.CreateNote(marshal(commitNote), project.LocalNoteNameSpace); err != nil {
+				return note.CommitNote{}, err
+			}
+		}
 		if err := saveAndPurgeMetrics(gtmPath, metricMap, commitMap, readonlyMap); err != nil {
 			return note.CommitNote{}, err
 		}
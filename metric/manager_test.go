@@ -7,6 +7,7 @@ package metric
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"testing"
 
@@ -132,3 +133,31 @@ func TestInterim(t *testing.T) {
 		t.Errorf("Process(true) - test interim, want total 300, got %d", commitNote.Total())
 	}
 }
+
+func TestProcessByMinute(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+
+	curDir, err := os.Getwd()
+	util.CheckFatal(t, err)
+	defer os.Chdir(curDir)
+
+	os.Chdir(repo.Workdir())
+
+	repo.SaveFile("event.go", "event", "")
+	repo.SaveFile("1458496803.event", project.GTMDir, filepath.Join("event", "event.go"))
+	repo.SaveFile("1458496943.event", project.GTMDir, filepath.Join("event", "event.go"))
+
+	commitNote, err := ProcessByMinute()
+	if err != nil {
+		t.Fatalf("ProcessByMinute(), want error nil, got %s", err)
+	}
+
+	if len(commitNote.Files) != 1 {
+		t.Fatalf("ProcessByMinute(), want 1 file, got %+v", commitNote.Files)
+	}
+	want := map[int64]int{1458496800: 60, 1458496860: 60, 1458496920: 60}
+	if !reflect.DeepEqual(want, commitNote.Files[0].Timeline) {
+		t.Errorf("ProcessByMinute(), want timeline %v, got %v", want, commitNote.Files[0].Timeline)
+	}
+}
@@ -288,18 +288,22 @@ func buildCommitMaps(metricMap map[string]FileMetric) (map[string]FileMetric, ma
 	return commitMap, readonlyMap, nil
 }
 
-// buildCommitNote creates a CommitNote for files in the commit and readonly maps in git repo at rootPath
+// buildCommitNote creates a CommitNote for files in the commit and readonly maps in git repo at rootPath,
+// timelines are by hour if downsample is true otherwise by epoch window
 func buildCommitNote(
 	rootPath string,
 	commitMap map[string]FileMetric,
-	readonlyMap map[string]FileMetric) (note.CommitNote, error) {
+	readonlyMap map[string]FileMetric,
+	downsample bool) (note.CommitNote, error) {
 
 	defer util.Profile()()
 
 	flsModified := []note.FileDetail{}
 
 	for _, fm := range commitMap {
-		fm.Downsample()
+		if downsample {
+			fm.Downsample()
+		}
 		status := "m"
 		if _, err := os.Stat(filepath.Join(rootPath, fm.SourceFile)); os.IsNotExist(err) {
 			status = "d"
@@ -311,7 +315,9 @@ func buildCommitNote(
 
 	flsReadonly := []note.FileDetail{}
 	for _, fm := range readonlyMap {
-		fm.Downsample()
+		if downsample {
+			fm.Downsample()
+		}
 		status := "r"
 		if _, err := os.Stat(filepath.Join(rootPath, fm.SourceFile)); os.IsNotExist(err) {
 			status = "d"
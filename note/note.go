@@ -11,36 +11,193 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/git-time-metric/gtm/project"
 	"github.com/git-time-metric/gtm/util"
 )
 
+var (
+	reHeader     = regexp.MustCompile(`\[ver:\d+,total:\d+](\[tz:([-+]\d{4})])?`)
+	reHeaderVals = regexp.MustCompile(`\d+`)
+)
+
 // CommitNote contains the time metrics for a commit
 type CommitNote struct {
 	Files []FileDetail
+	// Zone is the optional UTC offset of the recorder's time zone, i.e. -0500
+	Zone string
+}
+
+// Location returns the recorder's time zone, ok is false if the note does not have a time zone
+func (n CommitNote) Location() (loc *time.Location, ok bool) {
+	if n.Zone == "" {
+		return nil, false
+	}
+	t, err := time.Parse("-0700", n.Zone)
+	if err != nil {
+		return nil, false
+	}
+	return t.Location(), true
 }
 
 // FilterOutTerminal filters out terminal time from commit note
 func (n CommitNote) FilterOutTerminal() CommitNote {
+	return n.filter(func(f FileDetail) bool { return !f.IsTerminal() })
+}
+
+// FilterOutApp filters out app time from commit note
+func (n CommitNote) FilterOutApp() CommitNote {
+	return n.filter(func(f FileDetail) bool { return !f.IsApp() })
+}
+
+// Filter returns the commit note with only the files matching the filter
+func (n CommitNote) Filter(ff FileFilter) CommitNote {
+	if !ff.IsSet() {
+		return n
+	}
+	return n.filter(ff.Match)
+}
+
+// filter returns the commit note with only the files to keep
+func (n CommitNote) filter(keep func(f FileDetail) bool) CommitNote {
 	fds := []FileDetail{}
 	for _, f := range n.Files {
-		if !f.IsTerminal() {
+		if keep(f) {
 			fds = append(fds, f)
 		}
 	}
-	return CommitNote{Files: fds}
+	return CommitNote{Files: fds, Zone: n.Zone}
 }
 
-// FilterOutApp filters out app time from commit note
-func (n CommitNote) FilterOutApp() CommitNote {
+const (
+	// KindSource is time spent in source files
+	KindSource = "source"
+	// KindTerminal is time spent in the terminal
+	KindTerminal = "terminal"
+	// KindApp is time spent in apps other than the terminal
+	KindApp = "app"
+)
+
+// Kinds are the kinds of files in commit notes
+var Kinds = []string{KindSource, KindTerminal, KindApp}
+
+// FileStatuses are the statuses of files in commit notes, modified, read and deleted
+var FileStatuses = []string{"m", "r", "d"}
+
+// FileFilter selects the files of commit notes, a zero FileFilter selects all files
+type FileFilter struct {
+	// Include are globs of the files to keep, all files are kept if empty, see util.MatchGlob
+	Include []string
+	// Exclude are globs of the files to remove
+	Exclude []string
+	// Statuses are the file statuses to keep [m|r|d], all statuses are kept if empty
+	Statuses []string
+	// Kinds are the kinds of files to keep [source|terminal|app], all kinds are kept if empty
+	Kinds []string
+	// MinDuration is the minimum seconds spent in a file to keep it
+	MinDuration int
+}
+
+// NewFileFilter returns a FileFilter for comma separated globs, statuses and kinds and a minimum duration, i.e. 5m
+func NewFileFilter(include, exclude, statuses, kinds, minDuration string) (FileFilter, error) {
+	split := func(s string) []string {
+		vals := []string{}
+		for _, v := range strings.Split(s, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				vals = append(vals, v)
+			}
+		}
+		return vals
+	}
+
+	ff := FileFilter{
+		Include:  split(include),
+		Exclude:  split(exclude),
+		Statuses: split(strings.ToLower(statuses)),
+		Kinds:    split(strings.ToLower(kinds))}
+
+	for _, g := range append(append([]string{}, ff.Include...), ff.Exclude...) {
+		if err := util.ValidGlob(g); err != nil {
+			return FileFilter{}, err
+		}
+	}
+	for _, s := range ff.Statuses {
+		if !util.StringInSlice(FileStatuses, s) {
+			return FileFilter{}, fmt.Errorf("File status %s not valid, must be one of %s", s, strings.Join(FileStatuses, ","))
+		}
+	}
+	for _, k := range ff.Kinds {
+		if !util.StringInSlice(Kinds, k) {
+			return FileFilter{}, fmt.Errorf("File kind %s not valid, must be one of %s", k, strings.Join(Kinds, ","))
+		}
+	}
+
+	if minDuration = strings.TrimSpace(minDuration); minDuration != "" {
+		d, err := time.ParseDuration(minDuration)
+		if err != nil || d < 0 {
+			return FileFilter{}, fmt.Errorf("Minimum duration %s not valid, i.e. 30s or 5m", minDuration)
+		}
+		ff.MinDuration = int(d.Seconds())
+	}
+	return ff, nil
+}
+
+// IsSet returns true if the filter removes any files
+func (ff FileFilter) IsSet() bool {
+	return len(ff.Include) > 0 || len(ff.Exclude) > 0 || len(ff.Statuses) > 0 || len(ff.Kinds) > 0 || ff.MinDuration > 0
+}
+
+// Match returns true if the file is selected by the filter, globs only match source files
+func (ff FileFilter) Match(f FileDetail) bool {
+	if len(ff.Kinds) > 0 && !util.StringInSlice(ff.Kinds, f.Kind()) {
+		return false
+	}
+	if len(ff.Statuses) > 0 && !util.StringInSlice(ff.Statuses, f.Status) {
+		return false
+	}
+	if f.TimeSpent < ff.MinDuration {
+		return false
+	}
+
+	file := filepath.ToSlash(f.SourceFile)
+	isSource := f.Kind() == KindSource
+	if len(ff.Include) > 0 && !(isSource && util.MatchAnyGlob(ff.Include, file)) {
+		return false
+	}
+	if len(ff.Exclude) > 0 && isSource && util.MatchAnyGlob(ff.Exclude, file) {
+		return false
+	}
+	return true
+}
+
+// Clip returns the commit note with only the time spent within the date range.
+// Files without a timeline, i.e. notes reduced by a privacy level, are kept
+// in full if the commit's time is within the date range.
+func (n CommitNote) Clip(dateRange util.DateRange, committed time.Time) CommitNote {
 	fds := []FileDetail{}
 	for _, f := range n.Files {
-		if !f.IsApp() {
+		if len(f.Timeline) == 0 {
+			if dateRange.Within(committed) {
+				fds = append(fds, f)
+			}
+			continue
+		}
+		timeline := map[int64]int{}
+		total := 0
+		for epoch, secs := range f.Timeline {
+			if dateRange.Within(time.Unix(epoch, 0)) {
+				timeline[epoch] = secs
+				total += secs
+			}
+		}
+		if total > 0 {
+			f.Timeline = timeline
+			f.TimeSpent = total
 			fds = append(fds, f)
 		}
 	}
-	return CommitNote{Files: fds}
+	return CommitNote{Files: fds, Zone: n.Zone}
 }
 
 // Total returns the total time for a commit note
@@ -53,14 +210,23 @@ func (n CommitNote) Total() int {
 }
 
 // Marshal converts a commit note to a serialized string
+// Older versions require at least one epoch for each file, files without a timeline, i.e. notes reduced
+// by a privacy level, are serialized with an empty epoch as filename:total,0:0,status
 func Marshal(n CommitNote) string {
 	s := fmt.Sprintf("[ver:%s,total:%d]\n", "1", n.Total())
+	if n.Zone != "" {
+		// the zone follows the header so older versions, which only match [ver:n,total:n], can still read the note
+		s = fmt.Sprintf("[ver:%s,total:%d][tz:%s]\n", "1", n.Total(), n.Zone)
+	}
 	for _, fl := range n.Files {
 		// nomralize file paths to unix convention
 		s += fmt.Sprintf("%s:%d,", filepath.ToSlash(fl.SourceFile), fl.TimeSpent)
 		for _, e := range fl.SortEpochs() {
 			s += fmt.Sprintf("%d:%d,", e, fl.Timeline[e])
 		}
+		if len(fl.Timeline) == 0 {
+			s += "0:0,"
+		}
 		s += fmt.Sprintf("%s\n", fl.Status)
 	}
 	return s
@@ -70,12 +236,10 @@ func Marshal(n CommitNote) string {
 func UnMarshal(s string) (CommitNote, error) {
 	var (
 		version string
+		zone    string
 		files   = []FileDetail{}
 	)
 
-	reHeader := regexp.MustCompile(`\[ver:\d+,total:\d+]`)
-	reHeaderVals := regexp.MustCompile(`\d+`)
-
 	lines := strings.Split(s, "\n")
 	for lineIdx := 0; lineIdx < len(lines); lineIdx++ {
 		switch {
@@ -84,12 +248,15 @@ func UnMarshal(s string) (CommitNote, error) {
 		case reHeader.MatchString(lines[lineIdx]):
 			if matches := reHeaderVals.FindAllString(lines[lineIdx], 2); len(matches) == 2 {
 				version = matches[0]
+				if z := reHeader.FindStringSubmatch(lines[lineIdx])[2]; z != "" {
+					zone = z
+				}
 			} else {
 				return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, header format invalid, %s", lines[lineIdx])
 			}
 		case version == "1":
 			fieldGroups := strings.Split(lines[lineIdx], ",")
-			if len(fieldGroups) < 3 {
+			if len(fieldGroups) < 2 {
 				return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, format invalid, %s", lines[lineIdx])
 			}
 
@@ -124,6 +291,10 @@ func UnMarshal(s string) (CommitNote, error) {
 					if err != nil {
 						return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, format invalid, %s", err)
 					}
+					if e == 0 && t == 0 {
+						// empty epoch of a file without a timeline
+						continue
+					}
 					fileTimeline[e] = t
 				default:
 					// error
@@ -136,8 +307,8 @@ func UnMarshal(s string) (CommitNote, error) {
 			found := false
 			for idx := range files {
 				if files[idx].SourceFile == filePath {
+					files[idx].TimeSpent += fileTotal
 					for epoch, secs := range fileTimeline {
-						files[idx].TimeSpent += secs
 						files[idx].Timeline[epoch] += secs
 					}
 					// only change file status if modified or deleted
@@ -163,7 +334,7 @@ func UnMarshal(s string) (CommitNote, error) {
 		}
 	}
 	sort.Sort(sort.Reverse(FileByTime(files)))
-	return CommitNote{Files: files}, nil
+	return CommitNote{Files: files, Zone: zone}, nil
 }
 
 // FileDetail contains a source file's time metrics
@@ -208,6 +379,18 @@ func (f *FileDetail) IsApp() bool {
 	return project.AppEventFileContentRegex.MatchString(f.SourceFile)
 }
 
+// Kind returns the kind of file [source|terminal|app]
+func (f *FileDetail) Kind() string {
+	switch {
+	case f.IsTerminal():
+		return KindTerminal
+	case f.IsApp():
+		return KindApp
+	default:
+		return KindSource
+	}
+}
+
 // GetAppName returns the name of the App
 func (f *FileDetail) GetAppName() string {
 	name := project.AppEventFileContentRegex.FindStringSubmatch(f.SourceFile)[1]
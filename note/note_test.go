@@ -5,8 +5,20 @@
 package note
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/git-time-metric/gtm/util"
 )
 
 func TestUnMarshallTimeLog(t *testing.T) {
@@ -80,3 +92,333 @@ environment/drone/test.go:60,1460070000:60,r
 	}
 
 }
+
+func TestRedact(t *testing.T) {
+	n := CommitNote{
+		Files: []FileDetail{
+			{
+				SourceFile: "environment/drone/run-tests.sh",
+				TimeSpent:  725,
+				Timeline:   map[int64]int{int64(1460066400): 705, int64(1460070000): 20},
+				Status:     "m"},
+			{
+				SourceFile: ".gtm/terminal.app",
+				TimeSpent:  700,
+				Timeline:   map[int64]int{int64(1460066400): 540, int64(1460070000): 160},
+				Status:     "r"},
+		},
+	}
+
+	cases := []struct {
+		Level string
+		Want  string
+	}{
+		{PrivacyFull, "[ver:1,total:1425]\nenvironment/drone/run-tests.sh:725,1460066400:705,1460070000:20,m\n.gtm/terminal.app:700,1460066400:540,1460070000:160,r\n"},
+		{PrivacyFiles, "[ver:1,total:1425]\nenvironment/drone/run-tests.sh:725,0:0,m\n.gtm/terminal.app:700,0:0,r\n"},
+		{PrivacyHashed, "[ver:1,total:1425]\n#fed37cff7ff0a30f.sh:725,1460066400:705,1460070000:20,m\n.gtm/terminal.app:700,1460066400:540,1460070000:160,r\n"},
+		{PrivacyTotals, "[ver:1,total:1425]\n*:1425,0:0,m\n"},
+	}
+
+	for _, tc := range cases {
+		redacted, err := n.Redact(tc.Level, []byte("secret"))
+		if err != nil {
+			t.Fatalf("Redact(%s), want error nil got error %s", tc.Level, err)
+		}
+		got := Marshal(redacted)
+		if got != tc.Want {
+			t.Errorf("Redact(%s), want:\n%s\ngot:\n%s", tc.Level, tc.Want, got)
+		}
+
+		// reduced notes must still be readable
+		unmarshalled, err := UnMarshal(got)
+		if err != nil {
+			t.Errorf("UnMarshal(%s), want error nil got error %s", got, err)
+		}
+		if unmarshalled.Total() != n.Total() {
+			t.Errorf("UnMarshal(%s), want total %d got %d", got, n.Total(), unmarshalled.Total())
+		}
+		for _, f := range unmarshalled.Files {
+			if _, ok := f.Timeline[0]; ok {
+				t.Errorf("UnMarshal(%s), want empty epoch ignored got timeline %+v", got, f.Timeline)
+			}
+		}
+	}
+
+	if _, err := n.Redact("invalid", nil); err == nil {
+		t.Errorf("Redact(invalid), want error got nil")
+	}
+
+	// hashes depend on the project's secret
+	if hashPath("event.go", []byte("secret")) == hashPath("event.go", []byte("other")) {
+		t.Errorf("hashPath(event.go), want different hashes for different secrets")
+	}
+}
+
+func TestLoadPrivacySecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gtm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	secretPath := filepath.Join(dir, "privacy.secret")
+	secret, err := LoadPrivacySecret(secretPath)
+	if err != nil {
+		t.Fatalf("LoadPrivacySecret(%s), want error nil got error %s", secretPath, err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("LoadPrivacySecret(%s), want a 32 byte secret got %d bytes", secretPath, len(secret))
+	}
+
+	// the secret is kept so hashes are stable
+	got, err := LoadPrivacySecret(secretPath)
+	if err != nil {
+		t.Fatalf("LoadPrivacySecret(%s), want error nil got error %s", secretPath, err)
+	}
+	if !reflect.DeepEqual(secret, got) {
+		t.Errorf("LoadPrivacySecret(%s), want %v got %v", secretPath, secret, got)
+	}
+}
+
+func TestMarshalZone(t *testing.T) {
+	n := CommitNote{
+		Files: []FileDetail{
+			{
+				SourceFile: "environment/drone/test.go",
+				TimeSpent:  60,
+				Timeline:   map[int64]int{int64(1460070000): 60},
+				Status:     "r"},
+		},
+		Zone: "-0500",
+	}
+
+	s := Marshal(n)
+	want := "[ver:1,total:60][tz:-0500]\nenvironment/drone/test.go:60,1460070000:60,r\n"
+	if s != want {
+		t.Errorf("Marshal(%+v), want:\n%s\ngot:\n%s", n, want, s)
+	}
+
+	got, err := UnMarshal(s)
+	if err != nil {
+		t.Fatalf("UnMarshal(%s), want error nil got error %s", s, err)
+	}
+	if !reflect.DeepEqual(n, got) {
+		t.Errorf("UnMarshal(%s), want:\n%+v\ngot:\n%+v", s, n, got)
+	}
+
+	loc, ok := got.Location()
+	if !ok {
+		t.Fatalf("Location(), want time zone got none")
+	}
+	if _, offset := time.Unix(1460070000, 0).In(loc).Zone(); offset != -5*3600 {
+		t.Errorf("Location(), want offset %d got %d", -5*3600, offset)
+	}
+}
+
+func TestClip(t *testing.T) {
+	n := CommitNote{
+		Files: []FileDetail{
+			{SourceFile: "a.go", TimeSpent: 725, Timeline: map[int64]int{int64(1460066400): 705, int64(1460070000): 20}, Status: "m"},
+			{SourceFile: "b.go", TimeSpent: 60, Timeline: map[int64]int{int64(1460066400): 60}, Status: "m"},
+			{SourceFile: "c.go", TimeSpent: 300, Status: "m"},
+		},
+		Zone: "-0500",
+	}
+	dateRange := util.DateRange{Start: time.Unix(1460070000, 0)}
+
+	clipped := n.Clip(dateRange, time.Unix(1460073600, 0))
+	if len(clipped.Files) != 2 || clipped.Files[0].TimeSpent != 20 || len(clipped.Files[0].Timeline) != 1 || clipped.Zone != "-0500" {
+		t.Errorf("Clip(), want a.go with 20 seconds and c.go got %+v", clipped)
+	}
+	if clipped.Total() != 320 {
+		t.Errorf("Clip(), want total 320 got %d", clipped.Total())
+	}
+	if n.Files[0].TimeSpent != 725 || len(n.Files[0].Timeline) != 2 {
+		t.Errorf("Clip(), want the original note unchanged got %+v", n.Files[0])
+	}
+
+	// files without a timeline are excluded when committed outside of the date range
+	clipped = n.Clip(dateRange, time.Unix(1460066400, 0))
+	if clipped.Total() != 20 {
+		t.Errorf("Clip(), want total 20 got %d", clipped.Total())
+	}
+}
+
+func TestFilter(t *testing.T) {
+	n := CommitNote{
+		Files: []FileDetail{
+			{SourceFile: "api/handler.go", TimeSpent: 600, Status: "m"},
+			{SourceFile: "api/handler_test.go", TimeSpent: 300, Status: "m"},
+			{SourceFile: "web/api/client.js", TimeSpent: 120, Status: "r"},
+			{SourceFile: "README.md", TimeSpent: 20, Status: "d"},
+			{SourceFile: ".gtm/terminal.app", TimeSpent: 200, Status: "r"},
+			{SourceFile: ".gtm/browser.app", TimeSpent: 100, Status: "r"},
+		},
+		Zone: "-0500",
+	}
+
+	tests := []struct {
+		filter FileFilter
+		want   []string
+	}{
+		{FileFilter{}, []string{"api/handler.go", "api/handler_test.go", "web/api/client.js", "README.md", ".gtm/terminal.app", ".gtm/browser.app"}},
+		{FileFilter{Include: []string{"./api/"}}, []string{"api/handler.go", "api/handler_test.go"}},
+		{FileFilter{Include: []string{"api/**"}}, []string{"api/handler.go", "api/handler_test.go", "web/api/client.js"}},
+		{FileFilter{Include: []string{"api/"}, Exclude: []string{"*_test.go"}}, []string{"api/handler.go", "web/api/client.js"}},
+		{FileFilter{Exclude: []string{"*.go"}}, []string{"web/api/client.js", "README.md", ".gtm/terminal.app", ".gtm/browser.app"}},
+		{FileFilter{Statuses: []string{"m", "d"}}, []string{"api/handler.go", "api/handler_test.go", "README.md"}},
+		{FileFilter{Kinds: []string{KindTerminal, KindApp}}, []string{".gtm/terminal.app", ".gtm/browser.app"}},
+		{FileFilter{Kinds: []string{KindSource}, MinDuration: 300}, []string{"api/handler.go", "api/handler_test.go"}},
+	}
+
+	for _, tc := range tests {
+		filtered := n.Filter(tc.filter)
+		got := []string{}
+		for _, f := range filtered.Files {
+			got = append(got, f.SourceFile)
+		}
+		if !reflect.DeepEqual(tc.want, got) {
+			t.Errorf("Filter(%+v), want %v got %v", tc.filter, tc.want, got)
+		}
+		if filtered.Zone != "-0500" {
+			t.Errorf("Filter(%+v), want zone -0500 got %s", tc.filter, filtered.Zone)
+		}
+	}
+
+	if got := n.FilterOutTerminal().FilterOutApp().Total(); got != 1040 {
+		t.Errorf("FilterOutTerminal().FilterOutApp(), want total 1040 got %d", got)
+	}
+}
+
+func TestNewFileFilter(t *testing.T) {
+	ff, err := NewFileFilter(" ./api/ , *.go", "*_test.go", "M,r", "source", "5m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := FileFilter{
+		Include:     []string{"./api/", "*.go"},
+		Exclude:     []string{"*_test.go"},
+		Statuses:    []string{"m", "r"},
+		Kinds:       []string{"source"},
+		MinDuration: 300}
+	if !reflect.DeepEqual(want, ff) {
+		t.Errorf("NewFileFilter(), want %+v got %+v", want, ff)
+	}
+
+	if ff, err := NewFileFilter("", "", "", "", ""); err != nil || ff.IsSet() {
+		t.Errorf("NewFileFilter() with no filters, want a filter which is not set got %+v, %v", ff, err)
+	}
+
+	invalid := [][]string{
+		{"[", "", "", "", ""},
+		{"", "", "x", "", ""},
+		{"", "", "", "editor", ""},
+		{"", "", "", "", "5"},
+		{"", "", "", "", "-5m"},
+	}
+	for _, args := range invalid {
+		if _, err := NewFileFilter(args[0], args[1], args[2], args[3], args[4]); err == nil {
+			t.Errorf("NewFileFilter(%q), want error got nil", args)
+		}
+	}
+}
+
+func TestMarshalCompatible(t *testing.T) {
+	n := CommitNote{
+		Files: []FileDetail{
+			{
+				SourceFile: "environment/drone/test.go",
+				TimeSpent:  60,
+				Timeline:   map[int64]int{int64(1460070000): 60},
+				Status:     "r"},
+		},
+		Zone: "-0500",
+	}
+
+	notes := map[string]string{
+		"zone": Marshal(n),
+	}
+	for _, level := range PrivacyLevels {
+		redacted, err := n.Redact(level, []byte("secret"))
+		if err != nil {
+			t.Fatalf("Redact(%s), want error nil got error %s", level, err)
+		}
+		notes[level] = Marshal(redacted)
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes["signed"] = Sign(Marshal(n), "0123456789abcdef0123456789abcdef01234567", key)
+	for name, s := range notes {
+		got, err := unMarshalV1(s)
+		if err != nil {
+			t.Errorf("unMarshalV1(%s) %s note, want error nil got error %s", s, name, err)
+			continue
+		}
+		if got.Total() != n.Total() {
+			t.Errorf("unMarshalV1(%s) %s note, want total %d got %d", s, name, n.Total(), got.Total())
+		}
+	}
+}
+
+// unMarshalV1 is the note parser of versions before time zones, privacy levels and signatures
+// which notes must remain readable by
+func unMarshalV1(s string) (CommitNote, error) {
+	var (
+		version string
+		files   = []FileDetail{}
+	)
+
+	reHeader := regexp.MustCompile(`\[ver:\d+,total:\d+]`)
+	reHeaderVals := regexp.MustCompile(`\d+`)
+
+	for _, line := range strings.Split(s, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			version = ""
+		case reHeader.MatchString(line):
+			if matches := reHeaderVals.FindAllString(line, 2); len(matches) == 2 {
+				version = matches[0]
+			} else {
+				return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, header format invalid, %s", line)
+			}
+		case version == "1":
+			fieldGroups := strings.Split(line, ",")
+			if len(fieldGroups) < 3 {
+				return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, format invalid, %s", line)
+			}
+
+			f := FileDetail{Timeline: map[int64]int{}}
+			for groupIdx := range fieldGroups {
+				fieldVals := strings.Split(fieldGroups[groupIdx], ":")
+				switch {
+				case groupIdx == 0 && len(fieldVals) == 2:
+					t, err := strconv.Atoi(fieldVals[1])
+					if err != nil {
+						return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, format invalid, %s", err)
+					}
+					f.SourceFile, f.TimeSpent = fieldVals[0], t
+				case groupIdx == len(fieldGroups)-1 && len(fieldVals) == 1:
+					f.Status = fieldVals[0]
+				case len(fieldVals) == 2:
+					e, err := strconv.ParseInt(fieldVals[0], 10, 64)
+					if err != nil {
+						return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, format invalid, %s", err)
+					}
+					t, err := strconv.Atoi(fieldVals[1])
+					if err != nil {
+						return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, format invalid, %s", err)
+					}
+					f.Timeline[e] = t
+				default:
+					return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, format invalid")
+				}
+			}
+			files = append(files, f)
+		default:
+			return CommitNote{}, fmt.Errorf("Unable to unmarshal time logged, unknown version %s", version)
+		}
+	}
+	return CommitNote{Files: files}, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package note
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// PrivacyFull shares file paths and hourly timelines
+	PrivacyFull = "full"
+	// PrivacyFiles shares file paths without timelines
+	PrivacyFiles = "files"
+	// PrivacyHashed shares hashed file paths with timelines, hashes are keyed by a secret kept in the project
+	// so file paths can't be guessed by hashing likely paths, hashes only match within a project
+	PrivacyHashed = "hashed"
+	// PrivacyTotals shares only the commit's total time
+	PrivacyTotals = "totals"
+
+	// HiddenFile is the source file recorded for a note with totals only
+	HiddenFile = "*"
+)
+
+// PrivacyLevels are the valid privacy levels for commit notes
+var PrivacyLevels = []string{PrivacyFull, PrivacyFiles, PrivacyHashed, PrivacyTotals}
+
+// Redact returns a commit note reduced to the detail allowed by the privacy level,
+// secret is the key for hashing file paths with the hashed privacy level
+func (n CommitNote) Redact(level string, secret []byte) (CommitNote, error) {
+	switch level {
+	case "", PrivacyFull:
+		return n, nil
+	case PrivacyFiles:
+		fds := []FileDetail{}
+		for _, f := range n.Files {
+			fds = append(fds,
+				FileDetail{SourceFile: f.SourceFile, TimeSpent: f.TimeSpent, Timeline: map[int64]int{}, Status: f.Status})
+		}
+		return CommitNote{Files: fds, Zone: n.Zone}, nil
+	case PrivacyHashed:
+		fds := []FileDetail{}
+		for _, f := range n.Files {
+			sourceFile := f.SourceFile
+			// terminal and app entries do not reveal anything about the project
+			if !f.IsTerminal() && !f.IsApp() {
+				sourceFile = hashPath(sourceFile, secret)
+			}
+			fds = append(fds,
+				FileDetail{SourceFile: sourceFile, TimeSpent: f.TimeSpent, Timeline: f.Timeline, Status: f.Status})
+		}
+		return CommitNote{Files: fds, Zone: n.Zone}, nil
+	case PrivacyTotals:
+		if len(n.Files) == 0 {
+			return CommitNote{Files: []FileDetail{}, Zone: n.Zone}, nil
+		}
+		return CommitNote{
+			Files: []FileDetail{
+				{SourceFile: HiddenFile, TimeSpent: n.Total(), Timeline: map[int64]int{}, Status: "m"}},
+			Zone: n.Zone}, nil
+	default:
+		return CommitNote{}, fmt.Errorf("Privacy level %s is not valid", level)
+	}
+}
+
+// hashPath returns a one-way hash of a file path keyed by secret, the file extension is kept
+func hashPath(p string, secret []byte) string {
+	p = filepath.ToSlash(p)
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(p))
+	h := fmt.Sprintf("%x", mac.Sum(nil))
+	return "#" + h[:16] + path.Ext(p)
+}
+
+// LoadPrivacySecret reads the secret for hashing file paths, the secret is created if it does not exist
+func LoadPrivacySecret(secretPath string) ([]byte, error) {
+	b, err := ioutil.ReadFile(secretPath)
+	switch {
+	case err == nil:
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+		if err != nil || len(secret) == 0 {
+			return nil, fmt.Errorf("Unable to read privacy secret %s, format invalid", secretPath)
+		}
+		return secret, nil
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(secretPath, []byte(base64.StdEncoding.EncodeToString(secret)+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// IsHidden returns true if the file details were removed by a privacy level
+func (f *FileDetail) IsHidden() bool {
+	return f.SourceFile == HiddenFile
+}
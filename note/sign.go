@@ -0,0 +1,225 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package note
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SignatureSigned is the status of a note where every time entry has a valid signature from a trusted key
+	SignatureSigned = "signed"
+	// SignatureUnsigned is the status of a note without any signatures
+	SignatureUnsigned = "unsigned"
+	// SignatureInvalid is the status of a note with a bad, untrusted or missing signature
+	SignatureInvalid = "invalid"
+)
+
+var reSignature = regexp.MustCompile(`\[sig:([A-Za-z0-9+/=]+),([A-Za-z0-9+/=]+)]$`)
+
+// Sign adds an ed25519 signature of a serialized commit note and the SHA1 id of the commit it is for to the note's header.
+// The signature follows the header so older versions, which only match [ver:n,total:n], can still read the note.
+func Sign(payload, commitID string, key ed25519.PrivateKey) string {
+	if !strings.HasSuffix(payload, "\n") {
+		payload += "\n"
+	}
+	sig := ed25519.Sign(key, signedMessage(payload, commitID))
+	idx := strings.Index(payload, "\n")
+	return fmt.Sprintf("%s[sig:%s,%s]%s",
+		payload[:idx],
+		FormatPublicKey(key.Public().(ed25519.PublicKey)),
+		base64.StdEncoding.EncodeToString(sig),
+		payload[idx:])
+}
+
+// signedMessage returns the message signed for a time entry, it binds the entry to its commit
+// so a signed note can not be copied to another commit
+func signedMessage(block, commitID string) []byte {
+	return []byte("commit:" + commitID + "\n" + block)
+}
+
+// Verify checks the signatures of a git note string for the SHA1 commit id against the trusted public keys.
+// A note can contain multiple time entries, for example when commits are amended,
+// and each entry must be signed for the commit for the note to be considered signed.
+// Entries copied from the original commit by git commit --amend or rebase were signed for that commit and are invalid.
+// The status is returned with the reason a note is invalid.
+func Verify(s, commitID string, trusted []ed25519.PublicKey) (string, error) {
+	var (
+		block    string
+		sigLine  string
+		inBlock  bool
+		blocks   int
+		signed   int
+		firstErr error
+	)
+
+	closeBlock := func() {
+		if inBlock {
+			blocks++
+			if sigLine != "" {
+				if err := verifyBlock(block, commitID, sigLine, trusted); err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					signed++
+				}
+			}
+		}
+		inBlock = false
+		block = ""
+		sigLine = ""
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			closeBlock()
+		case reHeader.MatchString(line):
+			closeBlock()
+			inBlock = true
+			if loc := reSignature.FindStringIndex(line); loc != nil {
+				sigLine = line[loc[0]:]
+				line = line[:loc[0]]
+			}
+			block = line + "\n"
+		case inBlock:
+			block += line + "\n"
+		}
+	}
+	closeBlock()
+
+	switch {
+	case firstErr != nil:
+		return SignatureInvalid, firstErr
+	case signed == 0:
+		return SignatureUnsigned, nil
+	case signed < blocks:
+		return SignatureInvalid, fmt.Errorf("%d of %d time entries are not signed", blocks-signed, blocks)
+	default:
+		return SignatureSigned, nil
+	}
+}
+
+func verifyBlock(block, commitID, sigLine string, trusted []ed25519.PublicKey) error {
+	matches := reSignature.FindStringSubmatch(sigLine)
+	pub, err := ParsePublicKey(matches[1])
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(matches[2])
+	if err != nil {
+		return fmt.Errorf("signature format invalid, %s", err)
+	}
+	if !ed25519.Verify(pub, signedMessage(block, commitID), sig) {
+		return fmt.Errorf("signature does not match time data and commit")
+	}
+	for _, k := range trusted {
+		if bytes.Equal(k, pub) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signed with untrusted key %s", FormatPublicKey(pub))
+}
+
+// FormatPublicKey returns the base64 encoding of a public key
+func FormatPublicKey(pub ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// ParsePublicKey decodes a base64 encoded public key
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("public key format invalid, %s", err)
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key format invalid, want %d bytes got %d", ed25519.PublicKeySize, len(b))
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+// ParseTrustedKeys reads public keys, one per line followed by an optional comment
+// Blank lines and lines starting with # are ignored
+func ParseTrustedKeys(b []byte) ([]ed25519.PublicKey, error) {
+	keys := []ed25519.PublicKey{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, err := ParsePublicKey(strings.Fields(line)[0])
+		if err != nil {
+			return keys, fmt.Errorf("Unable to read trusted keys, line %d %s", lineNo, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, scanner.Err()
+}
+
+// LoadTrustedKeys reads the trusted public keys file if it exists
+// The public key of the signing key, if any, is always trusted
+func LoadTrustedKeys(trustedKeysPath string, signingKey ed25519.PrivateKey) ([]ed25519.PublicKey, error) {
+	keys := []ed25519.PublicKey{}
+	b, err := ioutil.ReadFile(trustedKeysPath)
+	switch {
+	case err == nil:
+		if keys, err = ParseTrustedKeys(b); err != nil {
+			return keys, err
+		}
+	case !os.IsNotExist(err):
+		return keys, err
+	}
+	if signingKey != nil {
+		keys = append(keys, signingKey.Public().(ed25519.PublicKey))
+	}
+	return keys, nil
+}
+
+// LoadSigningKey reads the private key used for signing notes
+// A nil key is returned if the key file does not exist
+func LoadSigningKey(keyPath string) (ed25519.PrivateKey, error) {
+	b, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("Unable to read signing key %s, format invalid", keyPath)
+	}
+	return ed25519.PrivateKey(key), nil
+}
+
+// GenerateSigningKey creates a new private key for signing notes and saves it to keyPath
+func GenerateSigningKey(keyPath string) (ed25519.PublicKey, error) {
+	if _, err := os.Stat(keyPath); err == nil {
+		return nil, fmt.Errorf("Signing key %s already exists", keyPath)
+	}
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package note
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry1 := "[ver:1,total:1425]\nenvironment/drone/run-tests.sh:725,1460066400:705,1460070000:20,m\nenvironment/drone/run-tests-cron.sh:700,1460066400:540,1460070000:160,m\n"
+	entry2 := "[ver:1,total:60]\nenvironment/drone/test.go:60,1460070000:60,r\n"
+	commit := "0123456789abcdef0123456789abcdef01234567"
+	other := "76543210fedcba9876543210fedcba9876543210"
+
+	cases := []struct {
+		Name    string
+		Note    string
+		Trusted []ed25519.PublicKey
+		Want    string
+	}{
+		{"unsigned", entry1, []ed25519.PublicKey{pub}, SignatureUnsigned},
+		{"signed", Sign(entry1, commit, key), []ed25519.PublicKey{pub}, SignatureSigned},
+		{"amended", Sign(entry1, commit, key) + "\n" + Sign(entry2, commit, otherKey), []ed25519.PublicKey{pub, otherPub}, SignatureSigned},
+		{"untrusted", Sign(entry1, commit, otherKey), []ed25519.PublicKey{pub}, SignatureInvalid},
+		{"tampered", strings.Replace(Sign(entry1, commit, key), "725", "925", 1), []ed25519.PublicKey{pub}, SignatureInvalid},
+		{"partially signed", Sign(entry1, commit, key) + "\n" + entry2, []ed25519.PublicKey{pub}, SignatureInvalid},
+		{"copied from another commit", Sign(entry1, other, key), []ed25519.PublicKey{pub}, SignatureInvalid},
+	}
+
+	for _, tc := range cases {
+		got, err := Verify(tc.Note, commit, tc.Trusted)
+		if got != tc.Want {
+			t.Errorf("Verify(%s), want %s got %s, %v", tc.Name, tc.Want, got, err)
+		}
+		if got == SignatureInvalid && err == nil {
+			t.Errorf("Verify(%s), want error got nil", tc.Name)
+		}
+	}
+
+	// signatures are ignored when reading time data
+	n, err := UnMarshal(Sign(entry1, commit, key) + "\n" + Sign(entry2, commit, otherKey))
+	if err != nil {
+		t.Fatalf("UnMarshal(signed note), want error nil got error %s", err)
+	}
+	if n.Total() != 1485 {
+		t.Errorf("UnMarshal(signed note), want total 1485 got %d", n.Total())
+	}
+}
+
+func TestParseTrustedKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := ParseTrustedKeys([]byte("# team keys\n\n" + FormatPublicKey(pub) + " jane@example.com\n"))
+	if err != nil {
+		t.Fatalf("ParseTrustedKeys, want error nil got error %s", err)
+	}
+	if len(keys) != 1 || !pub.Equal(keys[0]) {
+		t.Errorf("ParseTrustedKeys, want %s got %+v", FormatPublicKey(pub), keys)
+	}
+
+	if _, err := ParseTrustedKeys([]byte("invalid-key\n")); err == nil {
+		t.Errorf("ParseTrustedKeys(invalid-key), want error got nil")
+	}
+}
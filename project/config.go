@@ -0,0 +1,144 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+)
+
+const (
+	// ConfigFile is the name of the project's settings file within the gtm directory
+	ConfigFile = "config.json"
+	// PrivacySecretFile is the name of the secret for hashing file paths within the gtm directory
+	PrivacySecretFile = "privacy.secret"
+	// SigningKeyFile is the name of the private key file for signing notes within the gtm home directory
+	SigningKeyFile = "signing.key"
+	// TrustedKeysFile is the name of the public keys file within the git repo root directory
+	TrustedKeysFile = ".gtm-trusted-keys"
+	// UserConfigFile is the name of the user's settings file within the gtm home directory
+	UserConfigFile = "config.json"
+	// TemplatesDir is the name of the report templates directory within the gtm home directory
+	TemplatesDir = "templates"
+	// RepoTemplatesDir is the name of the report templates directory within the git repo root directory
+	RepoTemplatesDir = ".gtm-templates"
+)
+
+// GTMHomeDir is returned by HomeDir instead of the user's gtm directory when it is set,
+// tests set it to a temporary directory so they do not change the user's index, keys or cache
+var GTMHomeDir string
+
+// HomeDir returns the user's gtm directory, i.e. ~/.git-time-metric
+func HomeDir() (string, error) {
+	if GTMHomeDir != "" {
+		return GTMHomeDir, nil
+	}
+	u, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(u.HomeDir, ".git-time-metric"), nil
+}
+
+// SigningKeyPath returns the path of the private key used for signing notes
+func SigningKeyPath() (string, error) {
+	d, err := HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, SigningKeyFile), nil
+}
+
+// TemplateDirs returns the directories searched for report templates, the repo's directory is searched first
+func TemplateDirs(repoPath ...string) ([]string, error) {
+	dirs := []string{}
+	if len(repoPath) > 0 && repoPath[0] != "" {
+		dirs = append(dirs, filepath.Join(repoPath[0], RepoTemplatesDir))
+	}
+	d, err := HomeDir()
+	if err != nil {
+		return dirs, err
+	}
+	return append(dirs, filepath.Join(d, TemplatesDir)), nil
+}
+
+// Config contains the settings for a project
+type Config struct {
+	// Privacy is the level of detail saved in the shared commit notes
+	Privacy string `json:"privacy,omitempty"`
+	// KeepLocal saves full detail commit notes in the unshared local namespace
+	KeepLocal bool `json:"keepLocal,omitempty"`
+}
+
+// LoadConfig returns the settings for the project in the gtmPath directory
+func LoadConfig(gtmPath string) (Config, error) {
+	cfg := Config{}
+	raw, err := ioutil.ReadFile(filepath.Join(gtmPath, ConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	err = json.Unmarshal(raw, &cfg)
+	return cfg, err
+}
+
+func saveConfig(cfg Config, gtmPath string) error {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(gtmPath, ConfigFile), b, 0644)
+}
+
+// UserConfig contains the user's settings for all projects
+type UserConfig struct {
+	// TimeZone is the default time zone for reporting, i.e. local, utc, author or America/Chicago
+	TimeZone string `json:"timeZone,omitempty"`
+	// RecordTimeZone saves the recorder's time zone with commit notes
+	RecordTimeZone bool `json:"recordTimeZone,omitempty"`
+	// WeekStart is the first day of the week for weekly reporting, i.e. sunday or monday
+	WeekStart string `json:"weekStart,omitempty"`
+	// Categories are the rules for the categories report, they take precedence over the default rules
+	Categories CategoryRules `json:"categories,omitempty"`
+	// IssuePattern is the regular expression for issue keys in commit messages and branch names,
+	// the first group is the key if the pattern has a group, i.e. #([0-9]+)
+	IssuePattern string `json:"issuePattern,omitempty"`
+}
+
+// CategoryRules classify source files by language and category, for example
+//
+//	{"languages": {".tpl": "Go", "Jenkinsfile": "Groovy"}, "globs": {"test": ["*_spec.rb"], "generated": ["api/**"]}}
+//
+// A glob without a slash matches a file's name, a glob ending in /** matches a directory at any depth
+// and any other glob matches the slash separated path relative to the project's root.
+type CategoryRules struct {
+	// Languages maps file extensions or file names to languages
+	Languages map[string]string `json:"languages,omitempty"`
+	// Globs maps categories to glob patterns
+	Globs map[string][]string `json:"globs,omitempty"`
+}
+
+// LoadUserConfig returns the user's settings saved in the gtm home directory
+func LoadUserConfig() (UserConfig, error) {
+	cfg := UserConfig{}
+	d, err := HomeDir()
+	if err != nil {
+		return cfg, err
+	}
+	raw, err := ioutil.ReadFile(filepath.Join(d, UserConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	err = json.Unmarshal(raw, &cfg)
+	return cfg, err
+}
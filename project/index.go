@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
-	"os/user"
 	"path/filepath"
 	"sort"
 	"time"
@@ -90,11 +89,11 @@ func (i *Index) projects() []string {
 }
 
 func (i *Index) path() (string, error) {
-	u, err := user.Current()
+	d, err := HomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(u.HomeDir, ".git-time-metric", "project.json"), nil
+	return filepath.Join(d, "project.json"), nil
 }
 
 func (i *Index) load() error {
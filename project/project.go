@@ -39,10 +39,11 @@ var (
 			RE:      regexp.MustCompile(`(?s)[/:a-zA-Z0-9$_=()"\.\|\-\\ ]*gtm(.exe"|)\s+commit\s+--yes\.*`)},
 	}
 	// GitConfig is map of git configuration settings
+	// notes.rewriteref is a glob so the shared and the local gtm-data-local notes are both kept when amending and rebasing
 	GitConfig = map[string]string{
 		"alias.pushgtm":    "push origin refs/notes/gtm-data",
 		"alias.fetchgtm":   "fetch origin refs/notes/gtm-data:refs/notes/gtm-data",
-		"notes.rewriteref": "refs/notes/gtm-data"}
+		"notes.rewriteref": "refs/notes/gtm-data*"}
 	// GitIgnore is file ignore to apply to git repo
 	GitIgnore = "/.gtm/"
 )
@@ -50,6 +51,8 @@ var (
 const (
 	// NoteNameSpace is the gtm git note namespace
 	NoteNameSpace = "gtm-data"
+	// LocalNoteNameSpace is the git note namespace for full detail notes that are not pushed
+	LocalNoteNameSpace = "gtm-data-local"
 	// GTMDir is the subdir for gtm within the git repo root directory
 	GTMDir = ".gtm"
 )
@@ -66,6 +69,7 @@ const initMsgTpl string = `
 {{ print "terminal:" | printf "%17s" }} {{ .Terminal }}
 {{ print ".gitignore:" | printf "%17s" }} {{ .GitIgnore }}
 {{ print "tags:" | printf "%17s" }} {{.Tags }}
+{{ print "privacy:" | printf "%17s" }} {{ if .Privacy }}{{ .Privacy }}{{ else }}full{{ end }}{{ if .KeepLocal }} (full detail kept in refs/notes/{{ .LocalNoteNameSpace }}){{ end }}
 `
 const removeMsgTpl string = `
 {{print "Git Time Metric uninitialized for " (.ProjectPath) | printf (.HeaderFormat) }}
@@ -82,7 +86,8 @@ The following items have been removed.
 `
 
 // Initialize initializes a git repo for time tracking
-func Initialize(terminal bool, tags []string, clearTags bool) (string, error) {
+// If privacy is blank the project's current privacy settings are kept
+func Initialize(terminal bool, tags []string, clearTags bool, privacy string, keepLocal bool) (string, error) {
 	wd, err := os.Getwd()
 
 	if err != nil {
@@ -133,6 +138,18 @@ func Initialize(terminal bool, tags []string, clearTags bool) (string, error) {
 		return "", err
 	}
 
+	cfg, err := LoadConfig(gtmPath)
+	if err != nil {
+		return "", err
+	}
+	if privacy != "" {
+		cfg.Privacy = privacy
+		cfg.KeepLocal = keepLocal
+		if err := saveConfig(cfg, gtmPath); err != nil {
+			return "", err
+		}
+	}
+
 	if terminal {
 		if err := ioutil.WriteFile(filepath.Join(gtmPath, "terminal.app"), []byte(""), 0644); err != nil {
 			return "", err
@@ -163,13 +180,16 @@ func Initialize(terminal bool, tags []string, clearTags bool) (string, error) {
 	t := template.Must(template.New("msg").Parse(initMsgTpl))
 	err = t.Execute(b,
 		struct {
-			Tags         string
-			HeaderFormat string
-			ProjectPath  string
-			GitHooks     map[string]scm.GitHook
-			GitConfig    map[string]string
-			GitIgnore    string
-			Terminal     bool
+			Tags               string
+			HeaderFormat       string
+			ProjectPath        string
+			GitHooks           map[string]scm.GitHook
+			GitConfig          map[string]string
+			GitIgnore          string
+			Terminal           bool
+			Privacy            string
+			KeepLocal          bool
+			LocalNoteNameSpace string
 		}{
 			strings.Join(tags, " "),
 			headerFormat,
@@ -178,6 +198,9 @@ func Initialize(terminal bool, tags []string, clearTags bool) (string, error) {
 			GitConfig,
 			GitIgnore,
 			terminal,
+			cfg.Privacy,
+			cfg.KeepLocal,
+			LocalNoteNameSpace,
 		})
 
 	if err != nil {
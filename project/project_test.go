@@ -44,7 +44,7 @@ func TestInitialize(t *testing.T) {
 		t.Fatalf("Unable to initialize git repo, %s", string(b))
 	}
 
-	s, err := Initialize(false, []string{}, false)
+	s, err := Initialize(false, []string{}, false, "", false)
 	if err != nil {
 		t.Errorf("Initialize(), want error nil got error %s", err)
 	}
@@ -93,7 +93,7 @@ func TestInitialize(t *testing.T) {
 	}
 
 	// let's reinitialize with terminal tracking enabled
-	s, err = Initialize(true, []string{}, false)
+	s, err = Initialize(true, []string{}, false, "", false)
 	if err != nil {
 		t.Errorf("Initialize(true), want error nil got error %s", err)
 	}
@@ -169,7 +169,7 @@ func TestUninitialize(t *testing.T) {
 		t.Fatalf("Unable to initialize git repo, %s", string(b))
 	}
 
-	_, err = Initialize(false, []string{}, false)
+	_, err = Initialize(false, []string{}, false, "", false)
 	if err != nil {
 		t.Fatalf("Want error nil got error %s", err)
 	}
@@ -244,7 +244,7 @@ func TestClean(t *testing.T) {
 		t.Fatalf("Unable to initialize git repo, %s", string(b))
 	}
 
-	_, err = Initialize(false, []string{}, false)
+	_, err = Initialize(false, []string{}, false, "", false)
 	if err != nil {
 		t.Fatalf("Want error nil got error %s", err)
 	}
@@ -0,0 +1,151 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// RatesFile is the name of the billing rates file within the gtm home directory
+	RatesFile = "rates.json"
+	// DefaultCurrency is the currency of rates without a currency
+	DefaultCurrency = "USD"
+	// RoundPerCommit rounds the billable time of each commit
+	RoundPerCommit = "commit"
+	// RoundPerDay rounds the billable time of each invoice line item per day
+	RoundPerDay = "day"
+)
+
+// Rate is an hourly billing rate
+type Rate struct {
+	Hourly   float64 `json:"hourly"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+// Cents returns the hourly rate in hundredths of the currency
+func (r Rate) Cents() int64 {
+	return int64(r.Hourly*100 + 0.5)
+}
+
+// Rounding is the policy for rounding billable time
+type Rounding struct {
+	// Per is what billable time is rounded for [commit|day], blank rounds each commit
+	Per string `json:"per,omitempty"`
+	// Increment rounds billable time up to the next increment of minutes
+	Increment int `json:"increment,omitempty"`
+	// Minimum is the minimum billable minutes
+	Minimum int `json:"minimum,omitempty"`
+}
+
+// Validate returns an error if the rounding policy is not valid
+func (r Rounding) Validate() error {
+	if r.Per != "" && r.Per != RoundPerCommit && r.Per != RoundPerDay {
+		return fmt.Errorf("Rounding per %s is not valid, must be %s or %s", r.Per, RoundPerCommit, RoundPerDay)
+	}
+	if r.Increment < 0 {
+		return fmt.Errorf("Rounding increment %d is not valid", r.Increment)
+	}
+	if r.Minimum < 0 {
+		return fmt.Errorf("Rounding minimum %d is not valid", r.Minimum)
+	}
+	return nil
+}
+
+// Round returns the billable seconds for the time spent, time is rounded up
+// to the next increment and to the minimum when there is a rounding policy
+func (r Rounding) Round(secs int) int {
+	if (r.Increment == 0 && r.Minimum == 0) || secs == 0 {
+		return secs
+	}
+	if inc := r.Increment * 60; inc > 0 && secs%inc != 0 {
+		secs += inc - secs%inc
+	}
+	if min := r.Minimum * 60; secs < min {
+		secs = min
+	}
+	return secs
+}
+
+// Rates contains the hourly billing rates for projects, tags and authors, for example
+//
+//	{
+//	  "default": {"hourly": 100, "currency": "USD"},
+//	  "projects": {"gtm": {"hourly": 120}},
+//	  "tags": {"client-a": {"hourly": 150, "currency": "EUR"}},
+//	  "authors": {"jane@example.com": {"hourly": 90}},
+//	  "rounding": {"per": "commit", "increment": 15, "minimum": 30}
+//	}
+type Rates struct {
+	Default  Rate            `json:"default"`
+	Projects map[string]Rate `json:"projects,omitempty"`
+	Tags     map[string]Rate `json:"tags,omitempty"`
+	// Authors are keyed by author email or name
+	Authors  map[string]Rate `json:"authors,omitempty"`
+	Rounding Rounding        `json:"rounding,omitempty"`
+}
+
+// RatesPath returns the path of the billing rates file in the gtm home directory
+func RatesPath() (string, error) {
+	d, err := HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, RatesFile), nil
+}
+
+// LoadRates returns the billing rates in ratesPath, empty rates are returned if the file does not exist
+func LoadRates(ratesPath string) (Rates, error) {
+	rates := Rates{}
+	raw, err := ioutil.ReadFile(ratesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rates, nil
+		}
+		return rates, err
+	}
+	if err := json.Unmarshal(raw, &rates); err != nil {
+		return rates, fmt.Errorf("Unable to read rates %s, %s", ratesPath, err)
+	}
+	return rates, rates.Rounding.Validate()
+}
+
+// Rate returns the hourly rate for time spent, a project's rate takes precedence
+// over a tag's rate which takes precedence over an author's rate and the default rate.
+// Tags are checked in order and authors are matched by email and then by name.
+func (r Rates) Rate(project string, tags []string, author, email string) Rate {
+	rate, ok := r.Projects[project]
+	for i := 0; !ok && i < len(tags); i++ {
+		rate, ok = r.Tags[tags[i]]
+	}
+	if !ok {
+		rate, ok = r.lookupAuthor(author, email)
+	}
+	if !ok {
+		rate = r.Default
+	}
+	if rate.Currency == "" {
+		rate.Currency = r.Default.Currency
+	}
+	if rate.Currency == "" {
+		rate.Currency = DefaultCurrency
+	}
+	return rate
+}
+
+func (r Rates) lookupAuthor(author, email string) (Rate, bool) {
+	for k, rate := range r.Authors {
+		if email != "" && strings.EqualFold(k, email) {
+			return rate, true
+		}
+	}
+	rate, ok := r.Authors[author]
+	return rate, ok
+}
@@ -0,0 +1,95 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRatesRate(t *testing.T) {
+	rates := Rates{
+		Default:  Rate{Hourly: 100, Currency: "CAD"},
+		Projects: map[string]Rate{"gtm": {Hourly: 120}},
+		Tags:     map[string]Rate{"client": {Hourly: 150, Currency: "EUR"}},
+		Authors:  map[string]Rate{"Jane@Example.com": {Hourly: 90}, "John": {Hourly: 70}},
+	}
+	cases := []struct {
+		Project       string
+		Tags          []string
+		Author, Email string
+		Want          Rate
+	}{
+		{"gtm", []string{"client"}, "Jane", "jane@example.com", Rate{Hourly: 120, Currency: "CAD"}},
+		{"web", []string{"other", "client"}, "Jane", "jane@example.com", Rate{Hourly: 150, Currency: "EUR"}},
+		{"web", []string{}, "Jane", "jane@example.com", Rate{Hourly: 90, Currency: "CAD"}},
+		{"web", []string{}, "John", "", Rate{Hourly: 70, Currency: "CAD"}},
+		{"web", []string{}, "Bob", "bob@example.com", Rate{Hourly: 100, Currency: "CAD"}},
+	}
+	for _, tc := range cases {
+		if got := rates.Rate(tc.Project, tc.Tags, tc.Author, tc.Email); got != tc.Want {
+			t.Errorf("Rate(%s, %v, %s, %s), want %+v got %+v", tc.Project, tc.Tags, tc.Author, tc.Email, tc.Want, got)
+		}
+	}
+	if got := (Rates{}).Rate("gtm", nil, "", ""); got.Currency != DefaultCurrency {
+		t.Errorf("Rate() with empty rates, want currency %s got %s", DefaultCurrency, got.Currency)
+	}
+}
+
+func TestRoundingRound(t *testing.T) {
+	cases := []struct {
+		Rounding Rounding
+		Secs     int
+		Want     int
+	}{
+		{Rounding{}, 100, 100},
+		{Rounding{Increment: 15}, 100, 900},
+		{Rounding{Per: RoundPerCommit, Increment: 15}, 100, 900},
+		{Rounding{Per: RoundPerCommit, Increment: 15}, 900, 900},
+		{Rounding{Per: RoundPerDay, Increment: 6, Minimum: 30}, 400, 1800},
+		{Rounding{Per: RoundPerDay, Increment: 6, Minimum: 30}, 0, 0},
+	}
+	for _, tc := range cases {
+		if got := tc.Rounding.Round(tc.Secs); got != tc.Want {
+			t.Errorf("%+v Round(%d), want %d got %d", tc.Rounding, tc.Secs, tc.Want, got)
+		}
+	}
+}
+
+func TestLoadRates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gtm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rates, err := LoadRates(filepath.Join(dir, RatesFile))
+	if err != nil || rates.Default.Hourly != 0 {
+		t.Errorf("LoadRates() with missing file, want empty rates got %+v, %v", rates, err)
+	}
+
+	ratesPath := filepath.Join(dir, RatesFile)
+	raw := `{"default": {"hourly": 95.5}, "rounding": {"per": "day", "increment": 15}}`
+	if err := ioutil.WriteFile(ratesPath, []byte(raw), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rates, err = LoadRates(ratesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates.Default.Cents() != 9550 || rates.Rounding.Per != RoundPerDay || rates.Rounding.Increment != 15 {
+		t.Errorf("LoadRates(), want 95.50 rounded per day to 15 minutes got %+v", rates)
+	}
+
+	raw = `{"rounding": {"per": "week"}}`
+	if err := ioutil.WriteFile(ratesPath, []byte(raw), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadRates(ratesPath); err == nil {
+		t.Errorf("LoadRates() with rounding per week, want error got nil")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+// topFileCount is the number of files reported for each author
+const topFileCount = 5
+
+type authorEntries []authorEntry
+
+func (a authorEntries) Len() int      { return len(a) }
+func (a authorEntries) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a authorEntries) Less(i, j int) bool {
+	if a[i].Seconds != a[j].Seconds {
+		return a[i].Seconds < a[j].Seconds
+	}
+	return a[i].Name > a[j].Name
+}
+
+func (a authorEntries) Total() int {
+	total := 0
+	for _, entry := range a {
+		total += entry.Seconds
+	}
+	return total
+}
+
+type authorEntry struct {
+	Name    string
+	Email   string
+	Seconds int
+	// Commits is the number of commits with time spent
+	Commits int
+	// Files are the files the author spent the most time in
+	Files fileEntries
+}
+
+// Average returns the average time spent per commit
+func (a *authorEntry) Average() int {
+	if a.Commits == 0 {
+		return 0
+	}
+	return a.Seconds / a.Commits
+}
+
+func (a *authorEntry) Duration() string {
+	return util.FormatDuration(a.Seconds)
+}
+
+// authors returns the time spent by author, authors are matched by email
+// or by name if there is no email
+func (c commitNoteDetails) authors() authorEntries {
+	authorsMap := map[string]*authorEntry{}
+	type fileKey struct{ project, file string }
+	filesMap := map[string]map[fileKey]int{}
+	keys := []string{}
+
+	for _, n := range c {
+		key := strings.ToLower(n.Email)
+		if key == "" {
+			key = n.Author
+		}
+		entry, ok := authorsMap[key]
+		if !ok {
+			entry = &authorEntry{Name: n.Author, Email: n.Email}
+			authorsMap[key] = entry
+			filesMap[key] = map[fileKey]int{}
+			keys = append(keys, key)
+		}
+
+		total := n.Note.Total()
+		if total == 0 {
+			continue
+		}
+		entry.Seconds += total
+		entry.Commits++
+		for _, f := range n.Note.Files {
+			filesMap[key][fileKey{n.Project, f.SourceFile}] += f.TimeSpent
+		}
+	}
+
+	authors := make(authorEntries, 0, len(keys))
+	for _, key := range keys {
+		entry := authorsMap[key]
+		files := fileEntries{}
+		for k, secs := range filesMap[key] {
+			files = append(files, fileEntry{Project: k.project, Filename: k.file, Seconds: secs})
+		}
+		sort.Sort(sort.Reverse(files))
+		if len(files) > topFileCount {
+			files = files[:topFileCount]
+		}
+		entry.Files = files
+		authors = append(authors, *entry)
+	}
+	sort.Sort(sort.Reverse(authors))
+	return authors
+}
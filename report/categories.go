@@ -0,0 +1,206 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/util"
+)
+
+const (
+	// CategorySource is a source file which does not match a category's globs
+	CategorySource = "source"
+	// CategoryTerminal is time spent in the terminal
+	CategoryTerminal = "terminal"
+	// CategoryApp is time spent in apps other than the terminal
+	CategoryApp = "app"
+	// CategoryHidden is time spent in files not shared because of the project's privacy level
+	CategoryHidden = "hidden"
+	// LanguageOther is a source file with an unknown language
+	LanguageOther = "Other"
+)
+
+type categoryGlobs struct {
+	category string
+	globs    []string
+}
+
+// defaultCategoryGlobs are checked in order, the first matching category is used
+var defaultCategoryGlobs = []categoryGlobs{
+	{"generated", []string{"*.pb.go", "*_gen.go", "*.gen.go", "*_generated.go", "*.min.js", "*.min.css",
+		"*.lock", "go.sum", "package-lock.json", "vendor/**", "node_modules/**", "Godeps/**"}},
+	{"test", []string{"*_test.go", "*_test.py", "test_*.py", "*_spec.rb", "*_test.rb", "*.test.js", "*.spec.js",
+		"*.test.ts", "*.spec.ts", "*Test.java", "*Tests.cs", "test/**", "tests/**", "testdata/**", "spec/**", "__tests__/**"}},
+	{"build", []string{"Makefile", "*.mk", "Dockerfile", "*.dockerfile", "go.mod", "package.json", "CMakeLists.txt",
+		"pom.xml", "build.gradle", "*.gradle", "Rakefile", "Gemfile", "setup.py", ".travis.yml", "appveyor.yml",
+		"Jenkinsfile", ".github/**", "script/**", "scripts/**"}},
+	{"docs", []string{"*.md", "*.markdown", "*.rst", "*.adoc", "*.txt", "README*", "LICENSE*", "CHANGELOG*",
+		"AUTHORS*", "CONTRIBUTING*", "docs/**", "doc/**"}},
+	{"config", []string{"*.json", "*.yml", "*.yaml", "*.toml", "*.ini", "*.cfg", "*.conf", "*.properties",
+		".gitignore", ".gitattributes", ".editorconfig", ".env*", ".mailmap"}},
+}
+
+// defaultLanguages maps file extensions and file names to languages
+var defaultLanguages = map[string]string{
+	".go": "Go", ".c": "C", ".h": "C", ".cc": "C++", ".cpp": "C++", ".cxx": "C++", ".hpp": "C++",
+	".cs": "C#", ".java": "Java", ".kt": "Kotlin", ".scala": "Scala", ".groovy": "Groovy", ".clj": "Clojure",
+	".js": "JavaScript", ".jsx": "JavaScript", ".mjs": "JavaScript", ".ts": "TypeScript", ".tsx": "TypeScript",
+	".vue": "Vue", ".py": "Python", ".rb": "Ruby", ".php": "PHP", ".pl": "Perl", ".lua": "Lua", ".r": "R",
+	".rs": "Rust", ".swift": "Swift", ".m": "Objective-C", ".dart": "Dart", ".ex": "Elixir", ".exs": "Elixir",
+	".erl": "Erlang", ".hs": "Haskell", ".ml": "OCaml", ".fs": "F#", ".elm": "Elm", ".vim": "Vim script",
+	".sh": "Shell", ".bash": "Shell", ".zsh": "Shell", ".ps1": "PowerShell", ".sql": "SQL",
+	".html": "HTML", ".htm": "HTML", ".css": "CSS", ".scss": "SCSS", ".sass": "Sass", ".less": "Less",
+	".md": "Markdown", ".markdown": "Markdown", ".rst": "reStructuredText", ".adoc": "AsciiDoc", ".txt": "Text",
+	".json": "JSON", ".yml": "YAML", ".yaml": "YAML", ".toml": "TOML", ".xml": "XML", ".ini": "INI",
+	".proto": "Protocol Buffer", ".mk": "Makefile", ".dockerfile": "Dockerfile",
+	"Makefile": "Makefile", "Dockerfile": "Dockerfile", "Rakefile": "Ruby", "Gemfile": "Ruby", "Jenkinsfile": "Groovy",
+	"CMakeLists.txt": "CMake",
+}
+
+// classifier assigns a category and a language to source files
+type classifier struct {
+	languages map[string]string
+	globs     []categoryGlobs
+}
+
+// newClassifier returns a classifier with rules which take precedence over the defaults
+func newClassifier(rules project.CategoryRules) classifier {
+	c := classifier{languages: map[string]string{}, globs: []categoryGlobs{}}
+	for k, v := range defaultLanguages {
+		c.languages[k] = v
+	}
+	for k, v := range rules.Languages {
+		c.languages[k] = v
+	}
+
+	categories := make([]string, 0, len(rules.Globs))
+	for category := range rules.Globs {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		c.globs = append(c.globs, categoryGlobs{category, rules.Globs[category]})
+	}
+	c.globs = append(c.globs, defaultCategoryGlobs...)
+	return c
+}
+
+// classify returns a file's category and language, apps and hidden files do not have a language
+func (c classifier) classify(f note.FileDetail) (string, string) {
+	switch {
+	case f.IsTerminal():
+		return CategoryTerminal, ""
+	case f.IsApp():
+		return CategoryApp, ""
+	case f.IsHidden():
+		return CategoryHidden, ""
+	}
+
+	file := filepath.ToSlash(f.SourceFile)
+	category := CategorySource
+	for _, g := range c.globs {
+		if util.MatchAnyGlob(g.globs, file) {
+			category = g.category
+			break
+		}
+	}
+	return category, c.language(file)
+}
+
+// language returns the language for a file's name or extension
+func (c classifier) language(file string) string {
+	name := path.Base(file)
+	if l, ok := c.languages[name]; ok {
+		return l
+	}
+	if l, ok := c.languages[strings.ToLower(path.Ext(name))]; ok {
+		return l
+	}
+	return LanguageOther
+}
+
+type categoryEntry struct {
+	Name    string
+	Seconds int
+	// Commits is the number of commits with time spent in the category or language
+	Commits int
+}
+
+type categoryEntries []categoryEntry
+
+// Total returns the time spent in all entries
+func (c categoryEntries) Total() int {
+	total := 0
+	for _, e := range c {
+		total += e.Seconds
+	}
+	return total
+}
+
+type categoryBreakdown struct {
+	Categories categoryEntries
+	// Languages are for source files only, apps and hidden files are excluded
+	Languages categoryEntries
+}
+
+// categories returns the time spent by category and by language across all commits and projects
+func (c commitNoteDetails) categories(rules project.CategoryRules) categoryBreakdown {
+	cl := newClassifier(rules)
+	categories := map[string]*categoryEntry{}
+	languages := map[string]*categoryEntry{}
+
+	add := func(entries map[string]*categoryEntry, touched map[string]bool, name string, secs int) {
+		e, ok := entries[name]
+		if !ok {
+			e = &categoryEntry{Name: name}
+			entries[name] = e
+		}
+		e.Seconds += secs
+		touched[name] = true
+	}
+
+	for _, n := range c {
+		touchedCategories, touchedLanguages := map[string]bool{}, map[string]bool{}
+		for _, f := range n.Note.Files {
+			if f.TimeSpent == 0 {
+				continue
+			}
+			category, language := cl.classify(f)
+			add(categories, touchedCategories, category, f.TimeSpent)
+			if language != "" {
+				add(languages, touchedLanguages, language, f.TimeSpent)
+			}
+		}
+		for name := range touchedCategories {
+			categories[name].Commits++
+		}
+		for name := range touchedLanguages {
+			languages[name].Commits++
+		}
+	}
+
+	return categoryBreakdown{Categories: sortCategoryEntries(categories), Languages: sortCategoryEntries(languages)}
+}
+
+// sortCategoryEntries returns entries with the most time spent first
+func sortCategoryEntries(entries map[string]*categoryEntry) categoryEntries {
+	sorted := make(categoryEntries, 0, len(entries))
+	for _, e := range entries {
+		sorted = append(sorted, *e)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Seconds != sorted[j].Seconds {
+			return sorted[i].Seconds > sorted[j].Seconds
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,53 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/project"
+)
+
+func TestClassify(t *testing.T) {
+	rules := project.CategoryRules{
+		Languages: map[string]string{".tpl": "Go Template"},
+		Globs:     map[string][]string{"generated": {"api/*.go"}, "fixtures": {"fixtures/**"}},
+	}
+	cl := newClassifier(rules)
+
+	cases := []struct {
+		File     string
+		Category string
+		Language string
+	}{
+		{"main.go", CategorySource, "Go"},
+		{"event/event_test.go", "test", "Go"},
+		{"report/testdata/commits.json", "test", "JSON"},
+		{"vendor/github.com/pkg/errors/errors.go", "generated", "Go"},
+		{"api/service.go", "generated", "Go"},
+		{"api/v2/service.go", CategorySource, "Go"},
+		{"web/fixtures/users.json", "fixtures", "JSON"},
+		{"README.md", "docs", "Markdown"},
+		{"docs/index.html", "docs", "HTML"},
+		{"Makefile", "build", "Makefile"},
+		{".travis.yml", "build", "YAML"},
+		{"appveyor.yml", "build", "YAML"},
+		{"config.yml", "config", "YAML"},
+		{"report/page.tpl", CategorySource, "Go Template"},
+		{"LICENSE", "docs", LanguageOther},
+		{"Main.JAVA", CategorySource, "Java"},
+		{".gtm/terminal.app", CategoryTerminal, ""},
+		{".gtm/browser.app", CategoryApp, ""},
+		{note.HiddenFile, CategoryHidden, ""},
+	}
+
+	for _, tc := range cases {
+		category, language := cl.classify(note.FileDetail{SourceFile: tc.File})
+		if category != tc.Category || language != tc.Language {
+			t.Errorf("classify(%s), want %s %s got %s %s", tc.File, tc.Category, tc.Language, category, language)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/util"
+)
+
+// comparison is the time spent in projects, by authors and in file categories for two periods
+type comparison struct {
+	Projects   comparisonEntries
+	Authors    comparisonEntries
+	Categories comparisonEntries
+	Total      comparisonEntry
+}
+
+type comparisonSection struct {
+	Name    string
+	Entries comparisonEntries
+}
+
+// Sections returns the projects, authors and categories for display
+func (c comparison) Sections() []comparisonSection {
+	return []comparisonSection{{"Projects", c.Projects}, {"Authors", c.Authors}, {"Categories", c.Categories}}
+}
+
+type comparisonEntry struct {
+	Name     string
+	Current  int
+	Previous int
+}
+
+// Delta returns the change in time spent from the previous period
+func (c comparisonEntry) Delta() int {
+	return c.Current - c.Previous
+}
+
+// HasPercent returns false if there was no time spent in the previous period
+func (c comparisonEntry) HasPercent() bool {
+	return c.Previous != 0
+}
+
+// Percent returns the change in time spent as a percentage of the previous period
+func (c comparisonEntry) Percent() float64 {
+	return util.Percent(c.Delta(), c.Previous)
+}
+
+// FormatDelta returns the change in time spent with a sign, i.e. +1h 30m  0s
+func (c comparisonEntry) FormatDelta() string {
+	switch d := c.Delta(); {
+	case d > 0:
+		return "+" + util.FormatDuration(d)
+	case d < 0:
+		return "-" + util.FormatDuration(-d)
+	default:
+		return "0s"
+	}
+}
+
+// FormatPercent returns the change as a percentage with a sign or new if there was no time in the previous period
+func (c comparisonEntry) FormatPercent() string {
+	if !c.HasPercent() {
+		return "new"
+	}
+	return fmt.Sprintf("%+.0f%%", c.Percent())
+}
+
+type comparisonEntries []comparisonEntry
+
+// newComparisonEntries returns entries with the most time spent first
+func newComparisonEntries(current, previous map[string]int) comparisonEntries {
+	entries := comparisonEntries{}
+	for name, secs := range current {
+		entries = append(entries, comparisonEntry{Name: name, Current: secs, Previous: previous[name]})
+	}
+	for name, secs := range previous {
+		if _, ok := current[name]; !ok {
+			entries = append(entries, comparisonEntry{Name: name, Previous: secs})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.Current != b.Current:
+			return a.Current > b.Current
+		case a.Previous != b.Previous:
+			return a.Previous > b.Previous
+		default:
+			return a.Name < b.Name
+		}
+	})
+	return entries
+}
+
+// compareTotals returns the time spent by project, author and file category
+func (c commitNoteDetails) compareTotals(rules project.CategoryRules) (map[string]int, map[string]int, map[string]int) {
+	projects := map[string]int{}
+	for _, n := range c {
+		if secs := n.Note.Total(); secs > 0 {
+			projects[n.Project] += secs
+		}
+	}
+	authors := map[string]int{}
+	for _, a := range c.authors() {
+		if a.Seconds > 0 {
+			authors[a.Name] += a.Seconds
+		}
+	}
+	categories := map[string]int{}
+	for _, e := range c.categories(rules).Categories {
+		categories[e.Name] = e.Seconds
+	}
+	return projects, authors, categories
+}
+
+// compare returns the time spent in the current period compared to the previous period
+func compare(current, previous commitNoteDetails, rules project.CategoryRules) comparison {
+	curProjects, curAuthors, curCategories := current.compareTotals(rules)
+	prevProjects, prevAuthors, prevCategories := previous.compareTotals(rules)
+	return comparison{
+		Projects:   newComparisonEntries(curProjects, prevProjects),
+		Authors:    newComparisonEntries(curAuthors, prevAuthors),
+		Categories: newComparisonEntries(curCategories, prevCategories),
+		Total:      comparisonEntry{Name: "Total", Current: current.Total(), Previous: previous.Total()},
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/util"
+)
+
+func TestCompare(t *testing.T) {
+	c := compare(testNotes(), previousNotes(), project.CategoryRules{})
+
+	want := comparisonEntries{{"gtm", 6900, 2400}, {"web", 1800, 0}, {"api", 0, 900}}
+	if len(c.Projects) != len(want) {
+		t.Fatalf("compare() projects, want %+v got %+v", want, c.Projects)
+	}
+	for i := range want {
+		if c.Projects[i] != want[i] {
+			t.Errorf("compare() projects, want %+v got %+v", want[i], c.Projects[i])
+		}
+	}
+
+	if c.Total.Delta() != 5400 || c.Total.FormatPercent() != "+164%" {
+		t.Errorf("compare() total, want +5400 and +164%% got %d %s", c.Total.Delta(), c.Total.FormatPercent())
+	}
+	if web := c.Projects[1]; web.FormatPercent() != "new" || web.FormatDelta() != "+"+util.FormatDuration(1800) {
+		t.Errorf("compare() web, want new and +30m got %s %s", web.FormatPercent(), web.FormatDelta())
+	}
+	if api := c.Projects[2]; api.FormatPercent() != "-100%" {
+		t.Errorf("compare() api, want -100%% got %s", api.FormatPercent())
+	}
+
+	for _, category := range c.Categories {
+		if category.Name == "test" && (category.Current != 0 || category.Previous != 600) {
+			t.Errorf("compare() test category, want 0 and 600 got %+v", category)
+		}
+	}
+}
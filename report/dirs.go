@@ -0,0 +1,141 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+// rootDir is the directory for files in the root of a project
+const rootDir = "."
+
+type dirEntries []dirEntry
+
+func (d dirEntries) Len() int      { return len(d) }
+func (d dirEntries) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+
+// Less orders directories as a tree, parents before their children
+func (d dirEntries) Less(i, j int) bool {
+	if d[i].Project != d[j].Project {
+		return d[i].Project < d[j].Project
+	}
+	a, b := strings.Split(d[i].Dir, "/"), strings.Split(d[j].Dir, "/")
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// Total returns the time spent in all directories
+func (d dirEntries) Total() int {
+	total := 0
+	for _, entry := range d {
+		if entry.Depth == 1 {
+			total += entry.Seconds
+		}
+	}
+	return total
+}
+
+type dirEntry struct {
+	Project string
+	// Dir is the slash separated path relative to the project's root
+	Dir   string
+	Depth int
+	// Seconds is the time spent in the directory and its sub directories
+	Seconds int
+	// Commits is the number of commits with time spent in the directory
+	Commits int
+	// StartProject is true for the first directory of a project
+	StartProject bool
+}
+
+// Name returns the last element of the directory's path
+func (d *dirEntry) Name() string {
+	if d.Dir == rootDir {
+		return "./"
+	}
+	return path.Base(d.Dir) + "/"
+}
+
+// Indent returns the indentation for displaying the directory as a tree
+func (d *dirEntry) Indent() string {
+	return strings.Repeat("  ", d.Depth-1)
+}
+
+// Average returns the average time spent in the directory per commit
+func (d *dirEntry) Average() int {
+	if d.Commits == 0 {
+		return 0
+	}
+	return d.Seconds / d.Commits
+}
+
+func (d *dirEntry) Duration() string {
+	return util.FormatDuration(d.Seconds)
+}
+
+// dirs returns the time spent in source files rolled up by directory,
+// directories below depth are included in their parent, a depth of 0 has no limit
+func (c commitNoteDetails) dirs(depth int) dirEntries {
+	type key struct{ project, dir string }
+	entries := map[key]*dirEntry{}
+
+	for _, n := range c {
+		touched := map[key]bool{}
+		for _, f := range n.Note.Files {
+			if f.IsApp() || f.IsHidden() || f.TimeSpent == 0 {
+				continue
+			}
+			for _, d := range parentDirs(f.SourceFile, depth) {
+				k := key{n.Project, d}
+				entry, ok := entries[k]
+				if !ok {
+					entry = &dirEntry{Project: n.Project, Dir: d, Depth: strings.Count(d, "/") + 1}
+					entries[k] = entry
+				}
+				entry.Seconds += f.TimeSpent
+				touched[k] = true
+			}
+		}
+		for k := range touched {
+			entries[k].Commits++
+		}
+	}
+
+	dirs := make(dirEntries, 0, len(entries))
+	for _, entry := range entries {
+		dirs = append(dirs, *entry)
+	}
+	sort.Sort(dirs)
+	for i := range dirs {
+		dirs[i].StartProject = i == 0 || dirs[i].Project != dirs[i-1].Project
+	}
+	return dirs
+}
+
+// parentDirs returns a file's directory and its parents up to depth, i.e. a, a/b and a/b/c for a/b/c/d.go
+func parentDirs(file string, depth int) []string {
+	dir := path.Dir(filepath.ToSlash(file))
+	if dir == rootDir {
+		return []string{rootDir}
+	}
+	parts := strings.Split(dir, "/")
+	if depth > 0 && len(parts) > depth {
+		parts = parts[:depth]
+	}
+	dirs := make([]string, 0, len(parts))
+	for i := range parts {
+		dirs = append(dirs, strings.Join(parts[:i+1], "/"))
+	}
+	return dirs
+}
@@ -0,0 +1,94 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/git-time-metric/gtm/note"
+)
+
+func TestParentDirs(t *testing.T) {
+	cases := []struct {
+		File  string
+		Depth int
+		Want  []string
+	}{
+		{"main.go", 0, []string{"."}},
+		{"a/b/c/d.go", 0, []string{"a", "a/b", "a/b/c"}},
+		{"a/b/c/d.go", 2, []string{"a", "a/b"}},
+		{"a/d.go", 2, []string{"a"}},
+	}
+	for _, tc := range cases {
+		if got := parentDirs(tc.File, tc.Depth); !reflect.DeepEqual(got, tc.Want) {
+			t.Errorf("parentDirs(%s, %d), want %v got %v", tc.File, tc.Depth, tc.Want, got)
+		}
+	}
+}
+
+func TestDirs(t *testing.T) {
+	notes := commitNoteDetails{
+		{
+			Project: "gtm",
+			Note: note.CommitNote{Files: []note.FileDetail{
+				{SourceFile: "report/report.go", TimeSpent: 600},
+				{SourceFile: "report/testdata/commits.json", TimeSpent: 60},
+				{SourceFile: "report-tool/main.go", TimeSpent: 120},
+				{SourceFile: "main.go", TimeSpent: 300},
+				{SourceFile: ".gtm/terminal.app", TimeSpent: 900},
+			}},
+		},
+		{
+			Project: "gtm",
+			Note: note.CommitNote{Files: []note.FileDetail{
+				{SourceFile: "report/report.go", TimeSpent: 300},
+			}},
+		},
+	}
+
+	type dir struct {
+		Dir     string
+		Seconds int
+		Commits int
+	}
+	simplify := func(d dirEntries) []dir {
+		got := []dir{}
+		for _, e := range d {
+			got = append(got, dir{e.Dir, e.Seconds, e.Commits})
+		}
+		return got
+	}
+
+	got := notes.dirs(0)
+	want := []dir{
+		{".", 300, 1},
+		{"report", 960, 2},
+		{"report/testdata", 60, 1},
+		{"report-tool", 120, 1},
+	}
+	if !reflect.DeepEqual(simplify(got), want) {
+		t.Errorf("dirs(0), want %+v got %+v", want, simplify(got))
+	}
+	if got.Total() != 1380 {
+		t.Errorf("dirs(0).Total(), want 1380 got %d", got.Total())
+	}
+	if !got[0].StartProject || got[1].StartProject {
+		t.Errorf("dirs(0), want StartProject for the first directory only got %+v", got)
+	}
+	if got[1].Average() != 480 {
+		t.Errorf("dirs(0) report Average(), want 480 got %d", got[1].Average())
+	}
+
+	got = notes.dirs(1)
+	want = []dir{
+		{".", 300, 1},
+		{"report", 960, 2},
+		{"report-tool", 120, 1},
+	}
+	if !reflect.DeepEqual(simplify(got), want) {
+		t.Errorf("dirs(1), want %+v got %+v", want, simplify(got))
+	}
+}
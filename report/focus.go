@@ -0,0 +1,123 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+const (
+	// DefaultDeepWork is the seconds spent in a session of a single project for it to be deep work
+	DefaultDeepWork = 60 * 60
+	// focusSwitchPenalty is the points each project switch takes off a day's focus score
+	focusSwitchPenalty = 5
+)
+
+// focusDay is the fragmentation of the time spent on a day
+type focusDay struct {
+	Date            time.Time
+	Seconds         int
+	ProjectSwitches int
+	FileSwitches    int
+	// DeepWork are the sessions of a single project with at least the deep work time spent
+	DeepWork sessions
+}
+
+// FileSwitchesPerHour returns the file switches per hour of time spent
+func (d focusDay) FileSwitchesPerHour() float64 {
+	if d.Seconds == 0 {
+		return 0
+	}
+	return float64(d.FileSwitches) / (float64(d.Seconds) / 3600)
+}
+
+// Score returns the percentage of time spent in deep work less focusSwitchPenalty points for each project switch,
+// the score is from 0 to 100
+func (d focusDay) Score() int {
+	score := int(util.Percent(d.DeepWork.Total(), d.Seconds)+0.5) - d.ProjectSwitches*focusSwitchPenalty
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+type focusDays []focusDay
+
+// Total returns the days' fragmentation combined, the date is zero
+func (f focusDays) Total() focusDay {
+	total := focusDay{DeepWork: sessions{}}
+	for _, d := range f {
+		total.Seconds += d.Seconds
+		total.ProjectSwitches += d.ProjectSwitches
+		total.FileSwitches += d.FileSwitches
+		total.DeepWork = append(total.DeepWork, d.DeepWork...)
+	}
+	return total
+}
+
+// Score returns the average of the days' focus scores
+func (f focusDays) Score() int {
+	if len(f) == 0 {
+		return 0
+	}
+	total := 0
+	for _, d := range f {
+		total += d.Score()
+	}
+	return int(float64(total)/float64(len(f)) + 0.5)
+}
+
+// focus returns the project and file switches within the sessions and the deep work sessions with
+// at least deepWork seconds spent by the day the sessions start in loc
+func (s sessions) focus(loc *time.Location, deepWork int) focusDays {
+	projects := func(b sessionBucket) map[string]int { return b.Projects }
+	files := func(b sessionBucket) map[string]int { return b.Files }
+
+	days := focusDays{}
+	for _, d := range s.days(loc) {
+		fd := focusDay{Date: d.Date, Seconds: d.Sessions.Total(), DeepWork: sessions{}}
+		for _, x := range d.Sessions {
+			fd.ProjectSwitches += x.switches(projects)
+			fd.FileSwitches += x.switches(files)
+			if len(x.Projects) == 1 && x.Seconds >= deepWork {
+				fd.DeepWork = append(fd.DeepWork, x)
+			}
+		}
+		days = append(days, fd)
+	}
+	return days
+}
+
+// switches returns the number of times the project or file returned by names changes within the session.
+// The order of the names within a bucket is not known, the name active at the end of the previous bucket
+// is assumed to be first so the count is the fewest switches possible. Committed timelines are by hour
+// which undercounts switches, uncommitted timelines are by minute.
+func (s session) switches(names func(sessionBucket) map[string]int) int {
+	count := 0
+	last := ""
+	for _, b := range s.Buckets {
+		m := names(b)
+		ordered := []string{}
+		for name := range m {
+			if name != last {
+				ordered = append(ordered, name)
+			}
+		}
+		sort.Strings(ordered)
+		if _, ok := m[last]; ok {
+			ordered = append([]string{last}, ordered...)
+		}
+		for _, name := range ordered {
+			if last != "" && name != last {
+				count++
+			}
+			last = name
+		}
+	}
+	return count
+}
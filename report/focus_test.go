@@ -0,0 +1,40 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFocus(t *testing.T) {
+	days := testNotes().sessions(DefaultSessionGap).focus(time.UTC, DefaultDeepWork)
+	if len(days) != 1 {
+		t.Fatalf("focus(), want 1 day got %+v", days)
+	}
+
+	d := days[0]
+	// gtm and web are both active in the 16:00 bucket, event.go and then the other files
+	if d.Seconds != 8100 || d.ProjectSwitches != 1 || d.FileSwitches != 4 {
+		t.Errorf("focus(), want 8100 seconds, 1 project switch and 4 file switches got %d %d %d",
+			d.Seconds, d.ProjectSwitches, d.FileSwitches)
+	}
+	if len(d.DeepWork) != 1 || d.DeepWork[0].Seconds != 3900 ||
+		!d.DeepWork[0].Start.Equal(time.Date(2015, 6, 30, 21, 0, 0, 0, time.UTC)) {
+		t.Errorf("focus(), want a deep work session of 3900 seconds at 21:00 got %+v", d.DeepWork)
+	}
+	// 48% of the time in deep work less 5 points for the project switch
+	if d.Score() != 43 {
+		t.Errorf("focusDay.Score(), want 43 got %d", d.Score())
+	}
+	if got := roundTenth(d.FileSwitchesPerHour()); got != 1.8 {
+		t.Errorf("focusDay.FileSwitchesPerHour(), want 1.8 got %v", got)
+	}
+
+	// a longer threshold has no deep work
+	if days := testNotes().sessions(DefaultSessionGap).focus(time.UTC, 2*3600); len(days[0].DeepWork) != 0 {
+		t.Errorf("focus(2h), want no deep work got %+v", days[0].DeepWork)
+	}
+}
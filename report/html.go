@@ -0,0 +1,275 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+// OutputHTML is a single page html document with charts and tables
+const OutputHTML = "html"
+
+const (
+	chartHeight   = 200
+	chartBarWidth = 24
+	chartBarGap   = 8
+	heatmapCell   = 22
+)
+
+// chartColors is the palette for projects in the daily chart
+var chartColors = []string{
+	"#4e79a7", "#f28e2b", "#59a14f", "#e15759", "#76b7b2",
+	"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+}
+
+var htmlFuncMap = template.FuncMap{
+	"FormatDuration": util.FormatDuration,
+	"Percent":        util.Percent,
+	"Unix":           func(t time.Time) int64 { return t.Unix() },
+}
+
+type svgRect struct {
+	X, Y, Width, Height int
+	Color               string
+	Opacity             float64
+	Title               string
+}
+
+type svgText struct {
+	X, Y int
+	Text string
+}
+
+type htmlProject struct {
+	Name    string
+	Color   string
+	Seconds int
+}
+
+type htmlFile struct {
+	Project string
+	File    FileData
+}
+
+type htmlData struct {
+	Generated   string
+	From, To    string
+	Total       int
+	Projects    []htmlProject
+	Files       []htmlFile
+	Commits     commitsData
+	Chart       []svgRect
+	ChartLabels []svgText
+	ChartWidth  int
+	ChartHeight int
+	Heatmap     []svgRect
+	HeatLabels  []svgText
+}
+
+// HTML returns a single page report with a daily chart, an hour of day heatmap,
+// project and file breakdowns and a sortable table of commits
+func HTML(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return htmlReport(options.limitNotes(retrieveNotes(projects, options, true, "")), options)
+}
+
+func htmlReport(notes commitNoteDetails, options OutputOptions) (string, error) {
+	data := htmlData{
+		Generated: util.Now().Format("2006-01-02 15:04 MST"),
+		Total:     notes.Total(),
+		Commits:   notes.commitsData(),
+	}
+	if len(notes) > 0 {
+		// notes are sorted newest first
+		data.From = notes[len(notes)-1].When.Format("2006-01-02")
+		data.To = notes[0].When.Format("2006-01-02")
+	}
+
+	data.Projects = htmlProjects(notes)
+	colors := map[string]string{}
+	for _, p := range data.Projects {
+		colors[p.Name] = p.Color
+	}
+
+	data.Files = htmlFiles(notes)
+	data.Chart, data.ChartLabels, data.ChartWidth = dailyChart(notes, colors)
+	data.ChartHeight = chartHeight + 40
+	data.Heatmap, data.HeatLabels = hourHeatmap(notes)
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("HTML").Funcs(htmlFuncMap).Parse(htmlTpl))
+	if err := t.Execute(b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// htmlProjects returns the projects ordered by time spent
+func htmlProjects(notes commitNoteDetails) []htmlProject {
+	totals := map[string]int{}
+	for _, n := range notes {
+		totals[n.Project] += n.Note.Total()
+	}
+
+	projects := []htmlProject{}
+	for name, secs := range totals {
+		projects = append(projects, htmlProject{Name: name, Seconds: secs})
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Seconds != projects[j].Seconds {
+			return projects[i].Seconds > projects[j].Seconds
+		}
+		return projects[i].Name < projects[j].Name
+	})
+	for i := range projects {
+		projects[i].Color = chartColors[i%len(chartColors)]
+	}
+	return projects
+}
+
+// htmlFiles returns the time spent by file within each project ordered by time spent
+func htmlFiles(notes commitNoteDetails) []htmlFile {
+	type key struct{ project, file string }
+	totals := map[key]FileData{}
+	for _, n := range notes {
+		for _, f := range n.Note.Files {
+			k := key{n.Project, f.SourceFile}
+			d, ok := totals[k]
+			if !ok {
+				d = newFileData(f)
+				d.Status = ""
+				d.Seconds = 0
+			}
+			d.Seconds += f.TimeSpent
+			totals[k] = d
+		}
+	}
+
+	files := []htmlFile{}
+	for k, d := range totals {
+		files = append(files, htmlFile{Project: k.project, File: d})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].File.Seconds != files[j].File.Seconds {
+			return files[i].File.Seconds > files[j].File.Seconds
+		}
+		if files[i].Project != files[j].Project {
+			return files[i].Project < files[j].Project
+		}
+		return files[i].File.File < files[j].File.File
+	})
+	return files
+}
+
+// dailyChart returns the bars of a chart of time spent by day stacked by project
+func dailyChart(notes commitNoteDetails, colors map[string]string) ([]svgRect, []svgText, int) {
+	days := map[string]map[string]int{}
+	for _, n := range notes {
+		for _, f := range n.Note.Files {
+			for epoch, secs := range f.Timeline {
+				day := time.Unix(epoch, 0).In(n.When.Location()).Format("2006-01-02")
+				if _, ok := days[day]; !ok {
+					days[day] = map[string]int{}
+				}
+				days[day][n.Project] += secs
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(days))
+	max := 0
+	for day, projects := range days {
+		keys = append(keys, day)
+		total := 0
+		for _, secs := range projects {
+			total += secs
+		}
+		if total > max {
+			max = total
+		}
+	}
+	sort.Strings(keys)
+
+	projectNames := make([]string, 0, len(colors))
+	for p := range colors {
+		projectNames = append(projectNames, p)
+	}
+	sort.Strings(projectNames)
+
+	rects := []svgRect{}
+	labels := []svgText{}
+	for i, day := range keys {
+		x := chartBarGap + i*(chartBarWidth+chartBarGap)
+		y := chartHeight
+		for _, p := range projectNames {
+			secs := days[day][p]
+			if secs == 0 {
+				continue
+			}
+			h := secs * chartHeight / max
+			if h == 0 {
+				h = 1
+			}
+			y -= h
+			rects = append(rects, svgRect{
+				X: x, Y: y, Width: chartBarWidth, Height: h,
+				Color: colors[p], Opacity: 1,
+				Title: fmt.Sprintf("%s %s %s", day, p, util.FormatDuration(secs))})
+		}
+		labels = append(labels, svgText{X: x + chartBarWidth/2, Y: chartHeight + 14, Text: day[5:]})
+	}
+
+	width := chartBarGap + len(keys)*(chartBarWidth+chartBarGap)
+	if width < 300 {
+		width = 300
+	}
+	return rects, labels, width
+}
+
+// hourHeatmap returns the cells of a heatmap of time spent by weekday and hour
+func hourHeatmap(notes commitNoteDetails) ([]svgRect, []svgText) {
+	var cells [7][24]int
+	max := 0
+	for _, n := range notes {
+		for _, f := range n.Note.Files {
+			for epoch, secs := range f.Timeline {
+				t := time.Unix(epoch, 0).In(n.When.Location())
+				// weeks start on monday
+				wd := (int(t.Weekday()) + 6) % 7
+				cells[wd][t.Hour()] += secs
+				if cells[wd][t.Hour()] > max {
+					max = cells[wd][t.Hour()]
+				}
+			}
+		}
+	}
+
+	const left = 40
+	weekdays := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+	rects := []svgRect{}
+	labels := []svgText{}
+	for wd := range cells {
+		labels = append(labels, svgText{X: 4, Y: 20 + wd*heatmapCell + heatmapCell/2 + 4, Text: weekdays[wd]})
+		for h, secs := range cells[wd] {
+			opacity := 0.05
+			if secs > 0 && max > 0 {
+				opacity = 0.15 + 0.85*float64(secs)/float64(max)
+			}
+			rects = append(rects, svgRect{
+				X: left + h*heatmapCell, Y: 20 + wd*heatmapCell, Width: heatmapCell - 2, Height: heatmapCell - 2,
+				Color: chartColors[0], Opacity: opacity,
+				Title: fmt.Sprintf("%s %02d:00 %s", weekdays[wd], h, util.FormatDuration(secs))})
+		}
+	}
+	for h := 0; h < 24; h += 3 {
+		labels = append(labels, svgText{X: left + h*heatmapCell + 2, Y: 14, Text: fmt.Sprintf("%02d", h)})
+	}
+	return rects, labels
+}
@@ -0,0 +1,104 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+// OutputICS is an iCalendar file with an event for each work session
+const OutputICS = "ics"
+
+const (
+	icsTimeFormat = "20060102T150405Z"
+	// icsLineLength is the maximum octets of a line before it is folded
+	icsLineLength = 75
+)
+
+// Export returns the work sessions reconstructed from the commits' timelines in options.Output format
+func Export(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return export(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func export(notes commitNoteDetails, options OutputOptions) (string, error) {
+	if options.Output != OutputICS {
+		return "", fmt.Errorf("Output %s is not valid", options.Output)
+	}
+	return icsCalendar(notes.sessions(options.sessionGap()), util.Now()), nil
+}
+
+// icsCalendar returns an iCalendar with an event for each session, stamped with the time it was created
+func icsCalendar(s sessions, created time.Time) string {
+	b := new(bytes.Buffer)
+	line := func(name, value string) {
+		b.WriteString(icsFold(name + ":" + value))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//git-time-metric//gtm//EN")
+	line("CALSCALE", "GREGORIAN")
+	for _, x := range s {
+		description := []string{markdownDuration(x.Seconds) + " spent", ""}
+		for _, c := range x.Commits {
+			description = append(description, fmt.Sprintf("%s %s %s", c.Project, c.Hash, c.Subject))
+		}
+		description = append(description, "")
+		for _, f := range x.Files {
+			description = append(description, fmt.Sprintf("%s %s/%s", markdownDuration(f.Seconds), f.Project, f.SourceFile))
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", icsUID(x))
+		line("DTSTAMP", created.UTC().Format(icsTimeFormat))
+		line("DTSTART", x.Start.UTC().Format(icsTimeFormat))
+		line("DTEND", x.End.UTC().Format(icsTimeFormat))
+		line("SUMMARY", icsText(x.Title()))
+		line("DESCRIPTION", icsText(strings.Join(description, "\n")))
+		line("CATEGORIES", strings.Join(util.Map(x.Projects, icsText), ","))
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// icsUID returns an identifier for a session that is the same each time the session is exported
+func icsUID(s session) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "%d\x00%s", s.Start.Unix(), strings.Join(s.Projects, "\x00"))
+	return fmt.Sprintf("%x@git-time-metric", h.Sum(nil)[:10])
+}
+
+// icsText escapes text property values
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icsFold folds a content line longer than 75 octets without splitting characters,
+// continuation lines start with a space
+func icsFold(s string) string {
+	b := new(bytes.Buffer)
+	n := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if n+size > icsLineLength {
+			b.WriteString("\r\n ")
+			// the leading space counts towards the length
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
@@ -0,0 +1,140 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/git-time-metric/gtm/project"
+)
+
+type invoice struct {
+	Projects []invoiceProject
+	// Totals are the billable time and amount for each currency
+	Totals []invoiceTotal
+}
+
+type invoiceProject struct {
+	Project   string
+	Items     []invoiceItem
+	Subtotals []invoiceTotal
+}
+
+// invoiceItem is the billable time for commits with the same project, subject and rate
+type invoiceItem struct {
+	Project  string
+	Subject  string
+	Currency string
+	// Rate is the hourly rate in hundredths of the currency
+	Rate    int64
+	Commits int
+	// Seconds is the billable time after rounding
+	Seconds int
+	first   time.Time
+}
+
+// Amount returns the billable amount in hundredths of the currency
+func (i invoiceItem) Amount() int64 {
+	return (int64(i.Seconds)*i.Rate + 1800) / 3600
+}
+
+type invoiceTotal struct {
+	Currency string
+	Seconds  int
+	// Amount is in hundredths of the currency
+	Amount int64
+}
+
+// addTotal adds an item to the totals for the item's currency
+func addTotal(totals []invoiceTotal, item invoiceItem) []invoiceTotal {
+	for i := range totals {
+		if totals[i].Currency == item.Currency {
+			totals[i].Seconds += item.Seconds
+			totals[i].Amount += item.Amount()
+			return totals
+		}
+	}
+	totals = append(totals, invoiceTotal{Currency: item.Currency, Seconds: item.Seconds, Amount: item.Amount()})
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+	return totals
+}
+
+// invoice returns the billable time and amounts for commits grouped by project and commit subject,
+// billable time is rounded by commit or by day for each line item according to the rates' rounding policy
+func (c commitNoteDetails) invoice(rates project.Rates) invoice {
+	type itemKey struct {
+		project, subject, currency string
+		rate                       int64
+	}
+	items := map[itemKey]*invoiceItem{}
+	days := map[itemKey]map[string]int{}
+
+	for _, n := range c {
+		secs := n.Note.Total()
+		if secs == 0 {
+			continue
+		}
+		rate := rates.Rate(n.Project, n.Tags, n.Author, n.Email)
+		k := itemKey{n.Project, n.Subject, rate.Currency, rate.Cents()}
+		item, ok := items[k]
+		if !ok {
+			item = &invoiceItem{Project: n.Project, Subject: n.Subject, Currency: rate.Currency, Rate: rate.Cents()}
+			items[k] = item
+			days[k] = map[string]int{}
+		}
+		item.Commits++
+		if item.first.IsZero() || n.When.Before(item.first) {
+			item.first = n.When
+		}
+		if rates.Rounding.Per == project.RoundPerDay {
+			days[k][n.When.Format("2006-01-02")] += secs
+		} else {
+			item.Seconds += rates.Rounding.Round(secs)
+		}
+	}
+	for k, d := range days {
+		for _, secs := range d {
+			items[k].Seconds += rates.Rounding.Round(secs)
+		}
+	}
+
+	// line items are in the order they were first worked on
+	sorted := make([]invoiceItem, 0, len(items))
+	for _, item := range items {
+		sorted = append(sorted, *item)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch {
+		case a.Project != b.Project:
+			return a.Project < b.Project
+		case !a.first.Equal(b.first):
+			return a.first.Before(b.first)
+		case a.Subject != b.Subject:
+			return a.Subject < b.Subject
+		default:
+			return a.Rate < b.Rate
+		}
+	})
+
+	inv := invoice{Projects: []invoiceProject{}, Totals: []invoiceTotal{}}
+	for _, item := range sorted {
+		if len(inv.Projects) == 0 || inv.Projects[len(inv.Projects)-1].Project != item.Project {
+			inv.Projects = append(inv.Projects, invoiceProject{Project: item.Project, Subtotals: []invoiceTotal{}})
+		}
+		p := &inv.Projects[len(inv.Projects)-1]
+		p.Items = append(p.Items, item)
+		p.Subtotals = addTotal(p.Subtotals, item)
+		inv.Totals = addTotal(inv.Totals, item)
+	}
+	return inv
+}
+
+// formatMoney returns hundredths of a currency as a decimal amount, i.e. 1234.50
+func formatMoney(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
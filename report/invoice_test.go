@@ -0,0 +1,119 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/util"
+)
+
+func invoiceNotes() commitNoteDetails {
+	zone := time.FixedZone("-0500", -5*3600)
+	commit := func(proj, subject, email string, day, secs int) commitNoteDetail {
+		return commitNoteDetail{
+			Project: proj,
+			Subject: subject,
+			Author:  email,
+			Email:   email,
+			When:    time.Date(2015, 6, day, 9, 0, 0, 0, zone),
+			Note:    note.CommitNote{Files: []note.FileDetail{{SourceFile: "main.go", TimeSpent: secs}}},
+		}
+	}
+	return commitNoteDetails{
+		commit("gtm", "Add invoices", "jane@example.com", 2, 700),
+		commit("gtm", "Add invoices", "jane@example.com", 2, 200),
+		commit("gtm", "Add invoices", "jane@example.com", 3, 3000),
+		commit("gtm", "Fix typo", "jane@example.com", 1, 60),
+		commit("web", "Update styles", "john@example.com", 2, 1800),
+		commit("web", "Empty commit", "john@example.com", 2, 0),
+	}
+}
+
+func invoiceRates() project.Rates {
+	return project.Rates{
+		Default:  project.Rate{Hourly: 100},
+		Projects: map[string]project.Rate{"web": {Hourly: 80.5, Currency: "EUR"}},
+	}
+}
+
+func TestInvoice(t *testing.T) {
+	cases := []struct {
+		Rounding project.Rounding
+		// Seconds are the billable seconds for Fix typo, Add invoices and Update styles
+		Seconds [3]int
+	}{
+		{project.Rounding{}, [3]int{60, 3900, 1800}},
+		// each commit is rounded up to 15 minutes with a 30 minute minimum
+		{project.Rounding{Per: project.RoundPerCommit, Increment: 15, Minimum: 30}, [3]int{1800, 1800 + 1800 + 3600, 1800}},
+		// each line item is rounded up to 15 minutes by day
+		{project.Rounding{Per: project.RoundPerDay, Increment: 15}, [3]int{900, 900 + 3600, 1800}},
+	}
+
+	for _, tc := range cases {
+		rates := invoiceRates()
+		rates.Rounding = tc.Rounding
+		inv := invoiceNotes().invoice(rates)
+
+		if len(inv.Projects) != 2 || len(inv.Projects[0].Items) != 2 || len(inv.Projects[1].Items) != 1 {
+			t.Errorf("invoice(%+v), want 2 projects with 2 and 1 items got %+v", tc.Rounding, inv.Projects)
+			continue
+		}
+		got := [3]int{inv.Projects[0].Items[0].Seconds, inv.Projects[0].Items[1].Seconds, inv.Projects[1].Items[0].Seconds}
+		if got != tc.Seconds {
+			t.Errorf("invoice(%+v), want seconds %v got %v", tc.Rounding, tc.Seconds, got)
+		}
+	}
+
+	inv := invoiceNotes().invoice(invoiceRates())
+	if item := inv.Projects[0].Items[0]; item.Subject != "Fix typo" || item.Commits != 1 {
+		t.Errorf("invoice(), want first item Fix typo with 1 commit got %+v", item)
+	}
+	if item := inv.Projects[0].Items[1]; item.Commits != 3 || item.Amount() != 10833 {
+		t.Errorf("invoice(), want Add invoices with 3 commits and amount 10833 got %+v %d", item, item.Amount())
+	}
+	if item := inv.Projects[1].Items[0]; item.Currency != "EUR" || item.Rate != 8050 || item.Amount() != 4025 {
+		t.Errorf("invoice(), want Update styles at 80.50 EUR amount 4025 got %+v %d", item, item.Amount())
+	}
+	want := []invoiceTotal{{Currency: "EUR", Seconds: 1800, Amount: 4025}, {Currency: "USD", Seconds: 3960, Amount: 11000}}
+	if len(inv.Totals) != 2 || inv.Totals[0] != want[0] || inv.Totals[1] != want[1] {
+		t.Errorf("invoice(), want totals %+v got %+v", want, inv.Totals)
+	}
+}
+
+func TestInvoiceGolden(t *testing.T) {
+	zone := time.FixedZone("-0500", -5*3600)
+	options := OutputOptions{
+		Rates: invoiceRates(),
+		DateRange: util.DateRange{
+			Start: time.Date(2015, 6, 1, 0, 0, 0, 0, zone),
+			End:   time.Date(2015, 7, 1, 0, 0, 0, 0, zone).Add(-time.Nanosecond)},
+	}
+	for _, output := range []string{OutputJSON, OutputCSV} {
+		options.Output = output
+		got, err := invoiceReport(invoiceNotes(), options)
+		if err != nil {
+			t.Errorf("invoice -output %s, want error nil got %s", output, err)
+			continue
+		}
+		checkGolden(t, "invoice."+output, got)
+	}
+
+	// text output is not compared to a golden file because of terminal colors
+	options.Output = OutputText
+	got, err := invoiceReport(invoiceNotes(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Invoice 2015-06-01 - 2015-06-30", "1.08       100.00 USD       108.33 USD  Add invoices", "40.25 EUR  Total"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("invoice -output text, want %s got:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultIssuePattern matches issue keys such as PROJ-123
+const DefaultIssuePattern = `\b[A-Z][A-Z0-9]+-[0-9]+\b`
+
+// issueKeys returns the distinct issue keys in the texts in the order they are found,
+// if the pattern has a group the first group is the key, i.e. #([0-9]+)
+func issueKeys(re *regexp.Regexp, texts ...string) []string {
+	keys := []string{}
+	found := map[string]bool{}
+	for _, text := range texts {
+		for _, m := range re.FindAllStringSubmatch(text, -1) {
+			key := m[0]
+			if len(m) > 1 {
+				key = m[1]
+			}
+			if key == "" || found[key] {
+				continue
+			}
+			found[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// issueCommit is a commit's time spent on an issue
+type issueCommit struct {
+	Project string
+	Hash    string
+	Subject string
+	Seconds int
+}
+
+type issueEntry struct {
+	// Key is blank for commits without an issue key
+	Key      string
+	Seconds  int
+	Projects []string
+	Commits  []issueCommit
+}
+
+// ProjectList returns the projects with commits for the issue
+func (i issueEntry) ProjectList() string {
+	return strings.Join(i.Projects, ", ")
+}
+
+func (i *issueEntry) add(n commitNoteDetail, secs int) {
+	i.Seconds += secs
+	i.Commits = append(i.Commits, issueCommit{Project: n.Project, Hash: n.Hash, Subject: n.Subject, Seconds: secs})
+	for _, p := range i.Projects {
+		if p == n.Project {
+			return
+		}
+	}
+	i.Projects = append(i.Projects, n.Project)
+	sort.Strings(i.Projects)
+}
+
+type issueEntries []issueEntry
+
+// Total returns the time spent on all issues
+func (i issueEntries) Total() int {
+	total := 0
+	for _, e := range i {
+		total += e.Seconds
+	}
+	return total
+}
+
+type issueBreakdown struct {
+	Issues issueEntries
+	// Unkeyed are the commits without an issue key
+	Unkeyed issueEntry
+}
+
+// Total returns the time spent on issues and in commits without an issue key
+func (i issueBreakdown) Total() int {
+	return i.Issues.Total() + i.Unkeyed.Seconds
+}
+
+// issues returns the time spent by issue key across commits and projects, keys are found in the commit's
+// subject, message and branch. A commit with several keys has its time split evenly between them.
+// Commits only have a branch while they are on exactly one local branch, see scm.CommitBranches.
+func (c commitNoteDetails) issues(re *regexp.Regexp) issueBreakdown {
+	entries := map[string]*issueEntry{}
+	breakdown := issueBreakdown{Issues: issueEntries{}, Unkeyed: issueEntry{Projects: []string{}, Commits: []issueCommit{}}}
+
+	for _, n := range c {
+		total := n.Note.Total()
+		if total == 0 {
+			continue
+		}
+
+		keys := issueKeys(re, n.Subject, n.Message, n.Branch)
+		if len(keys) == 0 {
+			breakdown.Unkeyed.add(n, total)
+			continue
+		}
+
+		for i, key := range keys {
+			secs := total / len(keys)
+			if i == 0 {
+				// the first key has the remainder so the total is unchanged
+				secs += total % len(keys)
+			}
+			e, ok := entries[key]
+			if !ok {
+				e = &issueEntry{Key: key, Projects: []string{}, Commits: []issueCommit{}}
+				entries[key] = e
+			}
+			e.add(n, secs)
+		}
+	}
+
+	for _, e := range entries {
+		breakdown.Issues = append(breakdown.Issues, *e)
+	}
+	sort.Slice(breakdown.Issues, func(i, j int) bool {
+		a, b := breakdown.Issues[i], breakdown.Issues[j]
+		if a.Seconds != b.Seconds {
+			return a.Seconds > b.Seconds
+		}
+		return a.Key < b.Key
+	})
+	return breakdown
+}
@@ -0,0 +1,51 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestIssueKeys(t *testing.T) {
+	tests := []struct {
+		pattern string
+		texts   []string
+		want    []string
+	}{
+		{DefaultIssuePattern, []string{"PROJ-12 Fix PROJ-12", "", "WEB-3-quotes"}, []string{"PROJ-12", "WEB-3"}},
+		{DefaultIssuePattern, []string{"utf-8 and proj-1 are not keys", "SHA-256X"}, []string{}},
+		{`#([0-9]+)`, []string{"Fix #42, see #7", "#42"}, []string{"42", "7"}},
+	}
+	for _, tc := range tests {
+		got := issueKeys(regexp.MustCompile(tc.pattern), tc.texts...)
+		if !reflect.DeepEqual(tc.want, got) {
+			t.Errorf("issueKeys(%s, %q), want %v got %v", tc.pattern, tc.texts, tc.want, got)
+		}
+	}
+}
+
+func TestIssues(t *testing.T) {
+	notes := testNotes()
+	notes[2].Branch = "WEB-3-quotes"
+	breakdown := notes.issues(regexp.MustCompile(DefaultIssuePattern))
+
+	// the first commit's 3000 seconds are split between PROJ-12 and WEB-3, the second commit's 1800 are WEB-3
+	if len(breakdown.Issues) != 2 {
+		t.Fatalf("issues(), want 2 issues got %+v", breakdown.Issues)
+	}
+	web, proj := breakdown.Issues[0], breakdown.Issues[1]
+	if web.Key != "WEB-3" || web.Seconds != 3300 || len(web.Commits) != 2 || web.ProjectList() != "gtm, web" {
+		t.Errorf("issues(), want WEB-3 with 3300 seconds in 2 commits for gtm and web got %+v", web)
+	}
+	if proj.Key != "PROJ-12" || proj.Seconds != 1500 || len(proj.Commits) != 1 {
+		t.Errorf("issues(), want PROJ-12 with 1500 seconds in 1 commit got %+v", proj)
+	}
+	// uncommitted time has no key and the commit without time is not reported
+	if len(breakdown.Unkeyed.Commits) != 1 || breakdown.Unkeyed.Seconds != 3900 || breakdown.Total() != 8700 {
+		t.Errorf("issues(), want the uncommitted time without a key and a total of 8700 got %+v %d", breakdown.Unkeyed, breakdown.Total())
+	}
+}
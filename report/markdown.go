@@ -0,0 +1,71 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+// OutputMarkdown is github flavored markdown for pasting into pull requests and issues
+const OutputMarkdown = "markdown"
+
+// MarkdownFormats are the report formats that can be output as markdown
+var MarkdownFormats = []string{"commits", "files", "summary"}
+
+var markdownFuncMap = template.FuncMap{
+	"Duration": markdownDuration,
+	"Cell":     markdownCell,
+	"Code":     markdownCode,
+	"Percent":  util.Percent,
+}
+
+// markdownDuration formats a duration without the padding used for aligning text reports
+func markdownDuration(secs int) string {
+	return strings.Join(strings.Fields(util.FormatDuration(secs)), " ")
+}
+
+// markdownEscaper escapes markdown emphasis, code, links and html, html is escaped with entities
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "|", `\|`,
+	"&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markdownCell escapes a value for a markdown table cell
+func markdownCell(s string) string {
+	return markdownEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
+
+// markdownCode formats a value as inline code for a markdown table cell, the code is fenced with
+// more backticks than the value contains in a row since backslash escapes do not work in code
+func markdownCode(s string) string {
+	s = strings.Replace(strings.Join(strings.Fields(s), " "), "|", `\|`, -1)
+	run, longest := 0, 0
+	for _, r := range s {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		if run++; run > longest {
+			longest = run
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+func markdown(tpl string, data interface{}) (string, error) {
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Markdown").Funcs(markdownFuncMap).Parse(tpl))
+	if err := t.Execute(b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,990 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/util"
+)
+
+// Reports can be output as text or as structured data for scripting.
+//
+// The json output is an array of the report's data type, i.e. []CommitData for the commits report,
+// except for invoices and comparisons which are an InvoiceData or a CompareData object.
+// Field names are the json tags of the data types and are stable.
+//
+// The csv and tsv output is a header line followed by one row per record.
+// The columns for each report are:
+//
+//	commits           project,hash,date,author,subject,file,type,status,seconds (one row per file of a commit)
+//	summary           date,project,hash,subject,seconds (date is the first day of the period with -group-by)
+//	project           project,seconds or period,project,seconds with -group-by
+//	files             file,type,seconds
+//	timeline-hours    date,seconds,h00..h23 (seconds per hour)
+//	timeline-commits  date,commits,h00..h23 (commits per hour)
+//	dirs              project,dir,depth,seconds,commits,average
+//	timesheet         name,yyyy-mm-dd..,total (one column of seconds per day, one row per project or tag)
+//	authors           author,email,commits,seconds,average
+//	categories        type,name,commits,seconds,percent (type is category or language)
+//	sessions          type,date,start,end,seconds,projects,files (type is session or idle, files are project/file)
+//	focus             date,seconds,deepWorkSeconds,deepWork,projectSwitches,fileSwitches,fileSwitchesPerHour,score
+//	invoice           type,project,subject,commits,seconds,hours,rate,currency,amount (type is item, subtotal or total)
+//	compare           type,name,current,previous,delta,percent (type is project, author, category or total)
+//	status            project,tags,file,type,status,seconds (one row per file, tags are comma separated)
+//
+// Times are RFC 3339 timestamps, dates are yyyy-mm-dd and durations are whole seconds.
+const (
+	// OutputText is the human readable output
+	OutputText = "text"
+	// OutputJSON is a json array of records
+	OutputJSON = "json"
+	// OutputCSV is comma separated values with a header line
+	OutputCSV = "csv"
+	// OutputTSV is tab separated values with a header line
+	OutputTSV = "tsv"
+)
+
+// OutputFormats are the valid values for the output option
+var OutputFormats = []string{OutputText, OutputJSON, OutputCSV, OutputTSV}
+
+const (
+	// FileTypeFile is a source file in the project
+	FileTypeFile = "file"
+	// FileTypeApp is time spent in an app such as the terminal or a browser
+	FileTypeApp = "app"
+	// FileTypeHidden is time spent in files not shared because of the project's privacy level
+	FileTypeHidden = "hidden"
+)
+
+// FileData is the time spent in a file
+type FileData struct {
+	// Project is the file's project when files are reported across projects
+	Project string `json:"project,omitempty"`
+	// File is the path of the file relative to the project or the app's name
+	File string `json:"file"`
+	// Type is one of file, app or hidden
+	Type    string `json:"type"`
+	Status  string `json:"status,omitempty"`
+	Seconds int    `json:"seconds"`
+}
+
+// CommitData is the time data for a commit, uncommitted time is pending and has no hash
+type CommitData struct {
+	Project      string     `json:"project"`
+	Hash         string     `json:"hash"`
+	Date         time.Time  `json:"date"`
+	Author       string     `json:"author"`
+	Email        string     `json:"email"`
+	Subject      string     `json:"subject"`
+	Message      string     `json:"message"`
+	Seconds      int        `json:"seconds"`
+	LinesAdded   int        `json:"linesAdded"`
+	LinesDeleted int        `json:"linesDeleted"`
+	Signature    string     `json:"signature,omitempty"`
+	Pending      bool       `json:"pending,omitempty"`
+	Files        []FileData `json:"files"`
+}
+
+// SummaryCommit is a commit within a day of the summary report
+type SummaryCommit struct {
+	Project string `json:"project"`
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	Seconds int    `json:"seconds"`
+	// Pending is true for uncommitted time, it has no hash
+	Pending bool `json:"pending,omitempty"`
+}
+
+// SummaryData is the time spent for a day of the summary report
+// or for a period when grouped by week, month, quarter or year
+type SummaryData struct {
+	// Date is the day or the first day of the period
+	Date string `json:"date"`
+	// Period is the name of the period when grouped, i.e. 2015 Q2
+	Period  string          `json:"period,omitempty"`
+	Seconds int             `json:"seconds"`
+	Commits []SummaryCommit `json:"commits"`
+}
+
+// ProjectData is the time spent in a project
+type ProjectData struct {
+	// Period is the first day of the period when grouped, i.e. 2015-04-01
+	Period  string `json:"period,omitempty"`
+	Project string `json:"project"`
+	Seconds int    `json:"seconds"`
+}
+
+// TimelineData is the time spent in a day by hour
+type TimelineData struct {
+	Date    string  `json:"date"`
+	Seconds int     `json:"seconds"`
+	Hours   [24]int `json:"hours"`
+}
+
+// TimelineCommitData is the number of commits in a day by hour
+type TimelineCommitData struct {
+	Date    string  `json:"date"`
+	Commits int     `json:"commits"`
+	Hours   [24]int `json:"hours"`
+}
+
+// DirData is the time spent in a directory and its sub directories
+type DirData struct {
+	Project string `json:"project"`
+	// Dir is the slash separated path relative to the project's root, . for files in the root
+	Dir   string `json:"dir"`
+	Depth int    `json:"depth"`
+	// Commits is the number of commits with time spent in the directory
+	Commits int `json:"commits"`
+	Seconds int `json:"seconds"`
+	// Average is the average seconds per commit
+	Average int `json:"average"`
+}
+
+// TimesheetDay is the time spent on a day
+type TimesheetDay struct {
+	Date    string `json:"date"`
+	Seconds int    `json:"seconds"`
+}
+
+// TimesheetData is the time spent by day for a project or tag
+type TimesheetData struct {
+	Name    string         `json:"name"`
+	Seconds int            `json:"seconds"`
+	Days    []TimesheetDay `json:"days"`
+}
+
+// AuthorData is the time spent by an author
+type AuthorData struct {
+	Author string `json:"author"`
+	Email  string `json:"email"`
+	// Commits is the number of commits with time spent
+	Commits int `json:"commits"`
+	Seconds int `json:"seconds"`
+	// Average is the average seconds per commit
+	Average int `json:"average"`
+	// Files are the files the author spent the most time in
+	Files []FileData `json:"files"`
+}
+
+// CategoryData is the time spent in a category of files such as test or docs, or in a language
+type CategoryData struct {
+	// Type is category or language
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+	Seconds int    `json:"seconds"`
+	// Percent is the percentage of the total for the type, rounded to one decimal place
+	Percent float64 `json:"percent"`
+}
+
+// InvoiceItemData is the billable time for commits with the same project, subject and rate
+type InvoiceItemData struct {
+	Subject string `json:"subject"`
+	Commits int    `json:"commits"`
+	// Seconds is the billable time after rounding
+	Seconds  int     `json:"seconds"`
+	Rate     float64 `json:"rate"`
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+// InvoiceTotalData is the billable time and amount in a currency
+type InvoiceTotalData struct {
+	Currency string  `json:"currency"`
+	Seconds  int     `json:"seconds"`
+	Amount   float64 `json:"amount"`
+}
+
+// InvoiceProjectData is the line items and subtotals for a project
+type InvoiceProjectData struct {
+	Project   string             `json:"project"`
+	Items     []InvoiceItemData  `json:"items"`
+	Subtotals []InvoiceTotalData `json:"subtotals"`
+}
+
+// InvoiceData is the billable time and amounts for a date range
+type InvoiceData struct {
+	From     string               `json:"from,omitempty"`
+	To       string               `json:"to,omitempty"`
+	Projects []InvoiceProjectData `json:"projects"`
+	Totals   []InvoiceTotalData   `json:"totals"`
+}
+
+// IssueData is the time spent on an issue, the issue is blank for the commits without an issue key.
+// A commit with several issue keys has its time split evenly between the issues.
+type IssueData struct {
+	Issue    string          `json:"issue"`
+	Projects []string        `json:"projects"`
+	Seconds  int             `json:"seconds"`
+	Commits  []SummaryCommit `json:"commits"`
+}
+
+// SessionData is a contiguous period of work reconstructed from timelines, committed timelines record
+// the time spent in each hour so the start and end are estimates
+type SessionData struct {
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Seconds  int               `json:"seconds"`
+	Projects []string          `json:"projects"`
+	Commits  []SummaryCommit   `json:"commits"`
+	Files    []SessionFileData `json:"files"`
+}
+
+// SessionFileData is the time spent on a file in a session
+type SessionFileData struct {
+	Project string `json:"project"`
+	File    string `json:"file"`
+	Seconds int    `json:"seconds"`
+}
+
+// IdleData is the idle time between two sessions
+type IdleData struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Seconds int       `json:"seconds"`
+}
+
+// SessionDayData is the sessions starting on a day with the day's largest idle gaps, longest first
+type SessionDayData struct {
+	Date     string        `json:"date"`
+	Seconds  int           `json:"seconds"`
+	Sessions []SessionData `json:"sessions"`
+	Idle     []IdleData    `json:"idle"`
+}
+
+// DeepWorkData is a session of a single project with at least the deep work time spent
+type DeepWorkData struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Project string    `json:"project"`
+	Seconds int       `json:"seconds"`
+}
+
+// FocusData is the fragmentation of the time spent on a day, switches are counted within sessions
+// and the score is the percentage of time spent in deep work less 5 points for each project switch
+type FocusData struct {
+	Date                string         `json:"date"`
+	Seconds             int            `json:"seconds"`
+	DeepWorkSeconds     int            `json:"deepWorkSeconds"`
+	DeepWork            []DeepWorkData `json:"deepWork"`
+	ProjectSwitches     int            `json:"projectSwitches"`
+	FileSwitches        int            `json:"fileSwitches"`
+	FileSwitchesPerHour float64        `json:"fileSwitchesPerHour"`
+	Score               int            `json:"score"`
+}
+
+// ComparisonData is the change in time spent for a project, author or file category
+type ComparisonData struct {
+	Name string `json:"name"`
+	// Current and Previous are the seconds spent in each period
+	Current  int `json:"current"`
+	Previous int `json:"previous"`
+	Delta    int `json:"delta"`
+	// Percent is the change as a percentage of the previous period rounded to one decimal place,
+	// it is null if there was no time spent in the previous period
+	Percent *float64 `json:"percent"`
+}
+
+// CompareData is the time spent in a date range compared to the previous period
+type CompareData struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	PreviousFrom string           `json:"previousFrom"`
+	PreviousTo   string           `json:"previousTo"`
+	Projects     []ComparisonData `json:"projects"`
+	Authors      []ComparisonData `json:"authors"`
+	Categories   []ComparisonData `json:"categories"`
+	Total        ComparisonData   `json:"total"`
+}
+
+// StatusData is the pending time for a project
+type StatusData struct {
+	Project string     `json:"project"`
+	Tags    []string   `json:"tags"`
+	Seconds int        `json:"seconds"`
+	Files   []FileData `json:"files"`
+}
+
+// tabular is report data that can be output as rows of values
+type tabular interface {
+	header() []string
+	rows() [][]string
+}
+
+// render outputs report data in a structured format
+func render(output string, data tabular) (string, error) {
+	switch output {
+	case OutputJSON:
+		b, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	case OutputCSV, OutputTSV:
+		b := new(bytes.Buffer)
+		w := csv.NewWriter(b)
+		if output == OutputTSV {
+			w.Comma = '\t'
+		}
+		if err := w.Write(data.header()); err != nil {
+			return "", err
+		}
+		if err := w.WriteAll(data.rows()); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	default:
+		return "", fmt.Errorf("Output %s is not valid", output)
+	}
+}
+
+func newFileData(f note.FileDetail) FileData {
+	switch {
+	case f.IsApp():
+		return FileData{File: f.GetAppName(), Type: FileTypeApp, Status: f.Status, Seconds: f.TimeSpent}
+	case f.IsHidden():
+		return FileData{File: "", Type: FileTypeHidden, Status: f.Status, Seconds: f.TimeSpent}
+	default:
+		return FileData{File: f.SourceFile, Type: FileTypeFile, Status: f.Status, Seconds: f.TimeSpent}
+	}
+}
+
+func newFilesData(files []note.FileDetail) []FileData {
+	data := []FileData{}
+	for _, f := range files {
+		data = append(data, newFileData(f))
+	}
+	return data
+}
+
+func hourColumns() []string {
+	cols := make([]string, 24)
+	for i := range cols {
+		cols[i] = fmt.Sprintf("h%02d", i)
+	}
+	return cols
+}
+
+func hourValues(hours [24]int) []string {
+	vals := make([]string, 24)
+	for i, v := range hours {
+		vals[i] = strconv.Itoa(v)
+	}
+	return vals
+}
+
+type commitsData []CommitData
+
+func (c commitNoteDetails) commitsData() commitsData {
+	data := commitsData{}
+	for _, n := range c {
+		data = append(data, CommitData{
+			Project:      n.Project,
+			Hash:         n.ID,
+			Date:         n.When,
+			Author:       n.Author,
+			Email:        n.Email,
+			Subject:      n.Subject,
+			Message:      n.Message,
+			Seconds:      n.Note.Total(),
+			LinesAdded:   n.Insertions,
+			LinesDeleted: n.Deletions,
+			Signature:    n.Signature,
+			Pending:      n.Pending,
+			Files:        newFilesData(n.Note.Files),
+		})
+	}
+	return data
+}
+
+func (d commitsData) header() []string {
+	return []string{"project", "hash", "date", "author", "subject", "file", "type", "status", "seconds"}
+}
+
+func (d commitsData) rows() [][]string {
+	rows := [][]string{}
+	for _, c := range d {
+		date := c.Date.Format(time.RFC3339)
+		for _, f := range c.Files {
+			rows = append(rows,
+				[]string{c.Project, c.Hash, date, c.Author, c.Subject, f.File, f.Type, f.Status, strconv.Itoa(f.Seconds)})
+		}
+		if len(c.Files) == 0 {
+			rows = append(rows,
+				[]string{c.Project, c.Hash, date, c.Author, c.Subject, "", "", "", "0"})
+		}
+	}
+	return rows
+}
+
+type summaryData []SummaryData
+
+// summaryData returns the commits by day or by period if groupBy is set
+func (c commitNoteDetails) summaryData(groupBy string, weekStart time.Weekday) summaryData {
+	data := summaryData{}
+	for _, n := range c {
+		day := n.When.Format("2006-01-02")
+		period := ""
+		if groupBy != "" {
+			day = util.PeriodStart(groupBy, n.When, weekStart).Format("2006-01-02")
+			period = util.PeriodLabel(groupBy, n.When, weekStart)
+		}
+		if len(data) == 0 || data[len(data)-1].Date != day {
+			data = append(data, SummaryData{Date: day, Period: period, Commits: []SummaryCommit{}})
+		}
+		d := &data[len(data)-1]
+		d.Seconds += n.Note.Total()
+		d.Commits = append(d.Commits,
+			SummaryCommit{Project: n.Project, Hash: n.ID, Subject: n.Subject, Seconds: n.Note.Total(), Pending: n.Pending})
+	}
+	return data
+}
+
+func (d summaryData) header() []string {
+	return []string{"date", "project", "hash", "subject", "seconds"}
+}
+
+func (d summaryData) rows() [][]string {
+	rows := [][]string{}
+	for _, day := range d {
+		for _, c := range day.Commits {
+			rows = append(rows, []string{day.Date, c.Project, c.Hash, c.Subject, strconv.Itoa(c.Seconds)})
+		}
+	}
+	return rows
+}
+
+type projectsData []ProjectData
+
+func newProjectsData(projectTotals map[string]int) projectsData {
+	data := projectsData{}
+	for p, secs := range projectTotals {
+		data = append(data, ProjectData{Project: p, Seconds: secs})
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].Project < data[j].Project })
+	return data
+}
+
+func (d projectsData) header() []string {
+	return []string{"project", "seconds"}
+}
+
+func (d projectsData) rows() [][]string {
+	rows := [][]string{}
+	for _, p := range d {
+		rows = append(rows, []string{p.Project, strconv.Itoa(p.Seconds)})
+	}
+	return rows
+}
+
+// periodProjectsData is the time spent in projects by period
+type periodProjectsData []ProjectData
+
+func (p projectPeriods) periodProjectsData() periodProjectsData {
+	data := periodProjectsData{}
+	for _, period := range p {
+		for _, d := range period.Projects {
+			d.Period = period.Start.Format("2006-01-02")
+			data = append(data, d)
+		}
+	}
+	return data
+}
+
+func (d periodProjectsData) header() []string {
+	return []string{"period", "project", "seconds"}
+}
+
+func (d periodProjectsData) rows() [][]string {
+	rows := [][]string{}
+	for _, p := range d {
+		rows = append(rows, []string{p.Period, p.Project, strconv.Itoa(p.Seconds)})
+	}
+	return rows
+}
+
+type filesData []FileData
+
+func (f fileEntries) filesData() filesData {
+	data := filesData{}
+	for _, e := range f {
+		switch {
+		case e.IsApp():
+			data = append(data, FileData{Project: e.Project, File: e.GetAppName(), Type: FileTypeApp, Seconds: e.Seconds})
+		case e.IsHidden():
+			data = append(data, FileData{Project: e.Project, File: "", Type: FileTypeHidden, Seconds: e.Seconds})
+		default:
+			data = append(data, FileData{Project: e.Project, File: e.Filename, Type: FileTypeFile, Seconds: e.Seconds})
+		}
+	}
+	return data
+}
+
+type dirsData []DirData
+
+func (d dirEntries) dirsData() dirsData {
+	data := dirsData{}
+	for _, e := range d {
+		data = append(data, DirData{
+			Project: e.Project,
+			Dir:     e.Dir,
+			Depth:   e.Depth,
+			Commits: e.Commits,
+			Seconds: e.Seconds,
+			Average: e.Average(),
+		})
+	}
+	return data
+}
+
+func (d dirsData) header() []string {
+	return []string{"project", "dir", "depth", "seconds", "commits", "average"}
+}
+
+func (d dirsData) rows() [][]string {
+	rows := [][]string{}
+	for _, e := range d {
+		rows = append(rows, []string{
+			e.Project, e.Dir, strconv.Itoa(e.Depth), strconv.Itoa(e.Seconds), strconv.Itoa(e.Commits), strconv.Itoa(e.Average)})
+	}
+	return rows
+}
+
+// roundTenth rounds to one decimal place, halves are rounded away from zero
+func roundTenth(v float64) float64 {
+	return float64(int64(v*10+math.Copysign(0.5, v))) / 10
+}
+
+type categoriesData []CategoryData
+
+func (c categoryBreakdown) categoriesData() categoriesData {
+	data := categoriesData{}
+	add := func(typ string, entries categoryEntries) {
+		total := entries.Total()
+		for _, e := range entries {
+			data = append(data, CategoryData{
+				Type:    typ,
+				Name:    e.Name,
+				Commits: e.Commits,
+				Seconds: e.Seconds,
+				Percent: roundTenth(util.Percent(e.Seconds, total)),
+			})
+		}
+	}
+	add("category", c.Categories)
+	add("language", c.Languages)
+	return data
+}
+
+func (d categoriesData) header() []string {
+	return []string{"type", "name", "commits", "seconds", "percent"}
+}
+
+func (d categoriesData) rows() [][]string {
+	rows := [][]string{}
+	for _, c := range d {
+		rows = append(rows, []string{
+			c.Type, c.Name, strconv.Itoa(c.Commits), strconv.Itoa(c.Seconds), strconv.FormatFloat(c.Percent, 'f', 1, 64)})
+	}
+	return rows
+}
+
+type timesheetData struct {
+	dates []string
+	sheet []TimesheetData
+}
+
+func (t timesheet) timesheetData() timesheetData {
+	data := timesheetData{dates: []string{}, sheet: []TimesheetData{}}
+	for _, d := range t.Days {
+		data.dates = append(data.dates, d.Format("2006-01-02"))
+	}
+	for _, r := range t.Rows {
+		row := TimesheetData{Name: r.Name, Seconds: r.Total, Days: []TimesheetDay{}}
+		for i, secs := range r.Days {
+			row.Days = append(row.Days, TimesheetDay{Date: data.dates[i], Seconds: secs})
+		}
+		data.sheet = append(data.sheet, row)
+	}
+	return data
+}
+
+// MarshalJSON outputs the timesheet as an array of rows
+func (d timesheetData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.sheet)
+}
+
+func (d timesheetData) header() []string {
+	return append(append([]string{"name"}, d.dates...), "total")
+}
+
+func (d timesheetData) rows() [][]string {
+	rows := [][]string{}
+	for _, r := range d.sheet {
+		row := []string{r.Name}
+		for _, day := range r.Days {
+			row = append(row, strconv.Itoa(day.Seconds))
+		}
+		rows = append(rows, append(row, strconv.Itoa(r.Seconds)))
+	}
+	return rows
+}
+
+type authorsData []AuthorData
+
+func (a authorEntries) authorsData() authorsData {
+	data := authorsData{}
+	for _, e := range a {
+		data = append(data, AuthorData{
+			Author:  e.Name,
+			Email:   e.Email,
+			Commits: e.Commits,
+			Seconds: e.Seconds,
+			Average: e.Average(),
+			Files:   e.Files.filesData(),
+		})
+	}
+	return data
+}
+
+func (d authorsData) header() []string {
+	return []string{"author", "email", "commits", "seconds", "average"}
+}
+
+func (d authorsData) rows() [][]string {
+	rows := [][]string{}
+	for _, a := range d {
+		rows = append(rows, []string{a.Author, a.Email, strconv.Itoa(a.Commits), strconv.Itoa(a.Seconds), strconv.Itoa(a.Average)})
+	}
+	return rows
+}
+
+func (d filesData) header() []string {
+	return []string{"file", "type", "seconds"}
+}
+
+func (d filesData) rows() [][]string {
+	rows := [][]string{}
+	for _, f := range d {
+		rows = append(rows, []string{f.File, f.Type, strconv.Itoa(f.Seconds)})
+	}
+	return rows
+}
+
+type timelineData []TimelineData
+
+func (t timelineEntries) timelineData() timelineData {
+	data := timelineData{}
+	for _, e := range t {
+		data = append(data, TimelineData{Date: e.Date, Seconds: e.Seconds, Hours: e.Hours})
+	}
+	return data
+}
+
+func (d timelineData) header() []string {
+	return append([]string{"date", "seconds"}, hourColumns()...)
+}
+
+func (d timelineData) rows() [][]string {
+	rows := [][]string{}
+	for _, e := range d {
+		rows = append(rows, append([]string{e.Date, strconv.Itoa(e.Seconds)}, hourValues(e.Hours)...))
+	}
+	return rows
+}
+
+type timelineCommitData []TimelineCommitData
+
+func (t timelineCommitEntries) timelineCommitData() timelineCommitData {
+	data := timelineCommitData{}
+	for _, e := range t {
+		data = append(data, TimelineCommitData{Date: e.Date, Commits: e.Total, Hours: e.Commits})
+	}
+	return data
+}
+
+func (d timelineCommitData) header() []string {
+	return append([]string{"date", "commits"}, hourColumns()...)
+}
+
+func (d timelineCommitData) rows() [][]string {
+	rows := [][]string{}
+	for _, e := range d {
+		rows = append(rows, append([]string{e.Date, strconv.Itoa(e.Commits)}, hourValues(e.Hours)...))
+	}
+	return rows
+}
+
+type invoiceData InvoiceData
+
+func newInvoiceTotalsData(totals []invoiceTotal) []InvoiceTotalData {
+	data := []InvoiceTotalData{}
+	for _, t := range totals {
+		data = append(data, InvoiceTotalData{Currency: t.Currency, Seconds: t.Seconds, Amount: float64(t.Amount) / 100})
+	}
+	return data
+}
+
+func (i invoice) invoiceData(from, to string) invoiceData {
+	data := invoiceData{From: from, To: to, Projects: []InvoiceProjectData{}, Totals: newInvoiceTotalsData(i.Totals)}
+	for _, p := range i.Projects {
+		proj := InvoiceProjectData{Project: p.Project, Items: []InvoiceItemData{}, Subtotals: newInvoiceTotalsData(p.Subtotals)}
+		for _, item := range p.Items {
+			proj.Items = append(proj.Items, InvoiceItemData{
+				Subject:  item.Subject,
+				Commits:  item.Commits,
+				Seconds:  item.Seconds,
+				Rate:     float64(item.Rate) / 100,
+				Currency: item.Currency,
+				Amount:   float64(item.Amount()) / 100,
+			})
+		}
+		data.Projects = append(data.Projects, proj)
+	}
+	return data
+}
+
+func (d invoiceData) header() []string {
+	return []string{"type", "project", "subject", "commits", "seconds", "hours", "rate", "currency", "amount"}
+}
+
+func (d invoiceData) rows() [][]string {
+	hours := func(secs int) string { return fmt.Sprintf("%.2f", float64(secs)/3600) }
+	money := func(amount float64) string { return fmt.Sprintf("%.2f", amount) }
+
+	rows := [][]string{}
+	for _, p := range d.Projects {
+		for _, i := range p.Items {
+			rows = append(rows, []string{
+				"item", p.Project, i.Subject, strconv.Itoa(i.Commits), strconv.Itoa(i.Seconds),
+				hours(i.Seconds), money(i.Rate), i.Currency, money(i.Amount)})
+		}
+		for _, t := range p.Subtotals {
+			rows = append(rows, []string{
+				"subtotal", p.Project, "", "", strconv.Itoa(t.Seconds), hours(t.Seconds), "", t.Currency, money(t.Amount)})
+		}
+	}
+	for _, t := range d.Totals {
+		rows = append(rows, []string{
+			"total", "", "", "", strconv.Itoa(t.Seconds), hours(t.Seconds), "", t.Currency, money(t.Amount)})
+	}
+	return rows
+}
+
+type issuesData []IssueData
+
+func newIssueData(e issueEntry) IssueData {
+	d := IssueData{Issue: e.Key, Projects: e.Projects, Seconds: e.Seconds, Commits: []SummaryCommit{}}
+	for _, c := range e.Commits {
+		d.Commits = append(d.Commits, SummaryCommit{Project: c.Project, Hash: c.Hash, Subject: c.Subject, Seconds: c.Seconds})
+	}
+	return d
+}
+
+func (i issueBreakdown) issuesData() issuesData {
+	data := issuesData{}
+	for _, e := range i.Issues {
+		data = append(data, newIssueData(e))
+	}
+	if len(i.Unkeyed.Commits) > 0 {
+		data = append(data, newIssueData(i.Unkeyed))
+	}
+	return data
+}
+
+func (d issuesData) header() []string {
+	return []string{"issue", "projects", "commits", "seconds"}
+}
+
+func (d issuesData) rows() [][]string {
+	rows := [][]string{}
+	for _, i := range d {
+		rows = append(rows, []string{i.Issue, strings.Join(i.Projects, ","), strconv.Itoa(len(i.Commits)), strconv.Itoa(i.Seconds)})
+	}
+	return rows
+}
+
+type sessionsData []SessionDayData
+
+func newSessionsData(days []sessionDay) sessionsData {
+	data := sessionsData{}
+	for _, d := range days {
+		day := SessionDayData{
+			Date:     d.Date.Format("2006-01-02"),
+			Seconds:  d.Sessions.Total(),
+			Sessions: []SessionData{},
+			Idle:     []IdleData{}}
+		for _, s := range d.Sessions {
+			sd := SessionData{
+				Start:    s.Start,
+				End:      s.End,
+				Seconds:  s.Seconds,
+				Projects: s.Projects,
+				Commits:  []SummaryCommit{},
+				Files:    []SessionFileData{}}
+			for _, c := range s.Commits {
+				sd.Commits = append(sd.Commits,
+					SummaryCommit{Project: c.Project, Hash: c.Hash, Subject: c.Subject, Seconds: c.Seconds, Pending: c.Pending})
+			}
+			for _, f := range s.Files {
+				sd.Files = append(sd.Files, SessionFileData{Project: f.Project, File: f.SourceFile, Seconds: f.Seconds})
+			}
+			day.Sessions = append(day.Sessions, sd)
+		}
+		for _, g := range d.Gaps {
+			day.Idle = append(day.Idle, IdleData{Start: g.Start, End: g.End, Seconds: g.Seconds()})
+		}
+		data = append(data, day)
+	}
+	return data
+}
+
+func (d sessionsData) header() []string {
+	return []string{"type", "date", "start", "end", "seconds", "projects", "files"}
+}
+
+func (d sessionsData) rows() [][]string {
+	rows := [][]string{}
+	for _, day := range d {
+		for _, s := range day.Sessions {
+			files := []string{}
+			for _, f := range s.Files {
+				files = append(files, f.Project+"/"+f.File)
+			}
+			rows = append(rows, []string{
+				"session", day.Date, s.Start.Format(time.RFC3339), s.End.Format(time.RFC3339),
+				strconv.Itoa(s.Seconds), strings.Join(s.Projects, ","), strings.Join(files, ",")})
+		}
+		for _, g := range day.Idle {
+			rows = append(rows, []string{
+				"idle", day.Date, g.Start.Format(time.RFC3339), g.End.Format(time.RFC3339), strconv.Itoa(g.Seconds), "", ""})
+		}
+	}
+	return rows
+}
+
+type focusData []FocusData
+
+func newFocusData(days focusDays) focusData {
+	data := focusData{}
+	for _, d := range days {
+		fd := FocusData{
+			Date:                d.Date.Format("2006-01-02"),
+			Seconds:             d.Seconds,
+			DeepWorkSeconds:     d.DeepWork.Total(),
+			DeepWork:            []DeepWorkData{},
+			ProjectSwitches:     d.ProjectSwitches,
+			FileSwitches:        d.FileSwitches,
+			FileSwitchesPerHour: roundTenth(d.FileSwitchesPerHour()),
+			Score:               d.Score()}
+		for _, s := range d.DeepWork {
+			fd.DeepWork = append(fd.DeepWork, DeepWorkData{Start: s.Start, End: s.End, Project: s.Projects[0], Seconds: s.Seconds})
+		}
+		data = append(data, fd)
+	}
+	return data
+}
+
+func (d focusData) header() []string {
+	return []string{
+		"date", "seconds", "deepWorkSeconds", "deepWork", "projectSwitches", "fileSwitches", "fileSwitchesPerHour", "score"}
+}
+
+func (d focusData) rows() [][]string {
+	rows := [][]string{}
+	for _, f := range d {
+		rows = append(rows, []string{
+			f.Date, strconv.Itoa(f.Seconds), strconv.Itoa(f.DeepWorkSeconds), strconv.Itoa(len(f.DeepWork)),
+			strconv.Itoa(f.ProjectSwitches), strconv.Itoa(f.FileSwitches),
+			strconv.FormatFloat(f.FileSwitchesPerHour, 'f', 1, 64), strconv.Itoa(f.Score)})
+	}
+	return rows
+}
+
+type compareData CompareData
+
+func newComparisonData(e comparisonEntry) ComparisonData {
+	d := ComparisonData{Name: e.Name, Current: e.Current, Previous: e.Previous, Delta: e.Delta()}
+	if e.HasPercent() {
+		p := roundTenth(e.Percent())
+		d.Percent = &p
+	}
+	return d
+}
+
+func newComparisonsData(entries comparisonEntries) []ComparisonData {
+	data := []ComparisonData{}
+	for _, e := range entries {
+		data = append(data, newComparisonData(e))
+	}
+	return data
+}
+
+func (c comparison) compareData(current, previous util.DateRange) compareData {
+	return compareData{
+		From:         current.Start.Format("2006-01-02"),
+		To:           current.End.Format("2006-01-02"),
+		PreviousFrom: previous.Start.Format("2006-01-02"),
+		PreviousTo:   previous.End.Format("2006-01-02"),
+		Projects:     newComparisonsData(c.Projects),
+		Authors:      newComparisonsData(c.Authors),
+		Categories:   newComparisonsData(c.Categories),
+		Total:        newComparisonData(c.Total),
+	}
+}
+
+func (d compareData) header() []string {
+	return []string{"type", "name", "current", "previous", "delta", "percent"}
+}
+
+func (d compareData) rows() [][]string {
+	row := func(typ string, c ComparisonData) []string {
+		percent := ""
+		if c.Percent != nil {
+			percent = strconv.FormatFloat(*c.Percent, 'f', 1, 64)
+		}
+		return []string{typ, c.Name, strconv.Itoa(c.Current), strconv.Itoa(c.Previous), strconv.Itoa(c.Delta), percent}
+	}
+
+	rows := [][]string{}
+	for _, c := range d.Projects {
+		rows = append(rows, row("project", c))
+	}
+	for _, c := range d.Authors {
+		rows = append(rows, row("author", c))
+	}
+	for _, c := range d.Categories {
+		rows = append(rows, row("category", c))
+	}
+	return append(rows, row("total", d.Total))
+}
+
+type statusData []StatusData
+
+func (d statusData) header() []string {
+	return []string{"project", "tags", "file", "type", "status", "seconds"}
+}
+
+func (d statusData) rows() [][]string {
+	rows := [][]string{}
+	for _, s := range d {
+		tags := strings.Join(s.Tags, ",")
+		for _, f := range s.Files {
+			rows = append(rows, []string{s.Project, tags, f.File, f.Type, f.Status, strconv.Itoa(f.Seconds)})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,406 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/util"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// testNotes are uncommitted time by minute, a commit with issue keys, a commit with hidden files
+// in the same hour and in the evening and a commit without time
+func testNotes() commitNoteDetails {
+	zone := time.FixedZone("-0500", -5*3600)
+	// uncommitted time by minute from 21:00 to 22:05 UTC, a.go is edited before and after b.go
+	a, b := map[int64]int{}, map[int64]int{}
+	for m := int64(0); m < 65; m++ {
+		if m >= 30 && m < 60 {
+			b[1435698000+m*60] = 60
+		} else {
+			a[1435698000+m*60] = 60
+		}
+	}
+	return commitNoteDetails{
+		{
+			When:       time.Date(2015, 6, 30, 17, 5, 0, 0, zone),
+			Hash:       pendingHash,
+			Subject:    pendingSubject,
+			Project:    "gtm",
+			Pending:    true,
+			Resolution: 60,
+			Note: note.CommitNote{
+				Files: []note.FileDetail{
+					{SourceFile: "a.go", TimeSpent: 2100, Timeline: a, Status: "m"},
+					{SourceFile: "b.go", TimeSpent: 1800, Timeline: b, Status: "m"},
+				},
+			},
+		},
+		{
+			Author:     "Jane Doe",
+			Email:      "jane@example.com",
+			When:       time.Date(2015, 6, 30, 11, 30, 0, 0, zone),
+			ID:         "0123456789abcdef0123456789abcdef01234567",
+			Hash:       "0123456",
+			Subject:    "PROJ-12 Add event handling",
+			Message:    "Events are now handled\nby the event package\nSee also WEB-3 and PROJ-12",
+			Project:    "gtm",
+			Insertions: 120,
+			Deletions:  20,
+			Signature:  note.SignatureSigned,
+			Note: note.CommitNote{
+				Files: []note.FileDetail{
+					{
+						SourceFile: "event/event.go",
+						TimeSpent:  2700,
+						Timeline:   map[int64]int{1435676400: 1800, 1435680000: 900},
+						Status:     "m"},
+					{
+						SourceFile: ".gtm/terminal.app",
+						TimeSpent:  300,
+						Timeline:   map[int64]int{1435680000: 300},
+						Status:     "r"},
+				},
+			},
+		},
+		{
+			Author:  "John Doe",
+			Email:   "john@example.com",
+			When:    time.Date(2015, 6, 28, 16, 0, 0, 0, zone),
+			ID:      "89abcdef0123456789abcdef0123456789abcdef",
+			Hash:    "89abcde",
+			Subject: `Fix "quoted", comma subject`,
+			Project: "web",
+			Note: note.CommitNote{
+				Files: []note.FileDetail{
+					{
+						SourceFile: note.HiddenFile,
+						TimeSpent:  600,
+						Timeline:   map[int64]int{},
+						Status:     "m"},
+					{
+						SourceFile: "static/index.html",
+						TimeSpent:  1200,
+						Timeline:   map[int64]int{1435680000: 600, 1435690800: 600},
+						Status:     "m"},
+				},
+			},
+		},
+		{
+			Author:  "John",
+			Email:   "JOHN@example.com",
+			When:    time.Date(2015, 6, 28, 9, 0, 0, 0, zone),
+			ID:      "fedcba9876543210fedcba9876543210fedcba98",
+			Hash:    "fedcba9",
+			Subject: "Commit without time",
+			Project: "web",
+			Note:    note.CommitNote{Files: []note.FileDetail{}},
+		},
+	}
+}
+
+// previousNotes are the commits for the week before testNotes
+func previousNotes() commitNoteDetails {
+	zone := time.FixedZone("-0500", -5*3600)
+	return commitNoteDetails{
+		{
+			Author:  "Jane Doe",
+			Email:   "jane@example.com",
+			When:    time.Date(2015, 6, 23, 10, 0, 0, 0, zone),
+			Subject: "Add event package",
+			Project: "gtm",
+			Note: note.CommitNote{
+				Files: []note.FileDetail{
+					{SourceFile: "event/event.go", TimeSpent: 1800},
+					{SourceFile: "event/event_test.go", TimeSpent: 600},
+				},
+			},
+		},
+		{
+			Author:  "Sam Roe",
+			Email:   "sam@example.com",
+			When:    time.Date(2015, 6, 22, 10, 0, 0, 0, zone),
+			Subject: "Remove old handlers",
+			Project: "api",
+			Note:    note.CommitNote{Files: []note.FileDetail{{SourceFile: "handlers.go", TimeSpent: 900}}},
+		},
+	}
+}
+
+// compareNotes is the compare report of testNotes against previousNotes
+func compareNotes(notes commitNoteDetails, options OutputOptions) (string, error) {
+	zone := time.FixedZone("-0500", -5*3600)
+	options.DateRange = util.DateRange{
+		Start: time.Date(2015, 6, 28, 0, 0, 0, 0, zone),
+		End:   time.Date(2015, 7, 5, 0, 0, 0, 0, zone).Add(-time.Nanosecond)}
+	options.CompareRange = options.DateRange.Previous()
+	return compareReport(notes, previousNotes(), options)
+}
+
+// inUTC returns the report in UTC instead of the system's time zone
+func inUTC(report func(commitNoteDetails, OutputOptions) (string, error)) func(commitNoteDetails, OutputOptions) (string, error) {
+	return func(notes commitNoteDetails, options OutputOptions) (string, error) {
+		options.Location = time.UTC
+		return report(notes, options)
+	}
+}
+
+func checkGolden(t *testing.T, name, got string) {
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s, want:\n%s\ngot:\n%s", name, want, got)
+	}
+}
+
+func TestOutputGolden(t *testing.T) {
+	reports := []struct {
+		Name    string
+		Report  func(commitNoteDetails, OutputOptions) (string, error)
+		Outputs []string
+	}{
+		{"commits", commits, nil},
+		{"summary", commitSummary, nil},
+		{"project", projectSummary, nil},
+		{"files", files, nil},
+		{"timeline-hours", timeline, nil},
+		{"timeline-commits", timelineCommits, nil},
+		{"authors", authors, nil},
+		{"dirs", dirs, nil},
+		{"categories", categories, []string{OutputJSON, OutputCSV}},
+		{"compare", compareNotes, []string{OutputJSON, OutputCSV}},
+		{"issues", issues, []string{OutputJSON, OutputCSV}},
+		{"sessions", inUTC(sessionsReport), []string{OutputJSON, OutputCSV}},
+		{"focus", inUTC(focus), []string{OutputJSON, OutputCSV}},
+	}
+
+	for _, r := range reports {
+		outputs := r.Outputs
+		if outputs == nil {
+			outputs = []string{OutputJSON, OutputCSV, OutputTSV}
+		}
+		for _, output := range outputs {
+			got, err := r.Report(testNotes(), OutputOptions{Output: output})
+			if err != nil {
+				t.Errorf("%s -output %s, want error nil got %s", r.Name, output, err)
+				continue
+			}
+			checkGolden(t, r.Name+"."+output, got)
+		}
+	}
+}
+
+// text output is not compared to golden files because of terminal colors
+func TestOutputText(t *testing.T) {
+	reports := []struct {
+		Name   string
+		Report func(commitNoteDetails, OutputOptions) (string, error)
+		Want   []string
+	}{
+		{"categories", categories, []string{
+			"2h 10m  0s  90%     3 commits  source",
+			"5m  0s   3%     1 commits  terminal",
+			"20m  0s  15%     1 commits  HTML"}},
+		{"compare", compareNotes, []string{
+			"2015-06-28 - 2015-07-04 compared to 2015-06-21 - 2015-06-27",
+			"+30m  0s    new  web",
+			"-100%  Sam Roe"}},
+		{"issues", issues, []string{
+			"25m  0s  17%     1 commits  PROJ-12  gtm",
+			"25m  0s  17%     1 commits  WEB-3  gtm",
+			"Without an issue key",
+			"1h  5m  0s  pending (uncommitted) [gtm]",
+			"30m  0s  89abcde Fix \"quoted\", comma subject [web]"}},
+		{"sessions", inUTC(sessionsReport), []string{
+			"Tue Jun 30 2015",
+			"1h  0m  0s  15:30 - 16:30  gtm, web",
+			"45m  0s                 gtm/event/event.go",
+			"10m  0s  19:00 - 19:10  web",
+			"1h  5m  0s  21:00 - 22:05  gtm",
+			"2h 15m  0s",
+			"2h 30m  0s  16:30 - 19:00  idle",
+			"1h 50m  0s  19:10 - 21:00  idle"}},
+		{"focus", inUTC(focus), []string{
+			"Tue Jun 30 2015     2h 15m  0s     1h  5m  0s      1                1              1.8    43",
+			"1h  5m  0s  Tue Jun 30 21:00 - 22:05  gtm"}},
+	}
+
+	for _, r := range reports {
+		got, err := r.Report(testNotes(), OutputOptions{})
+		if err != nil {
+			t.Errorf("%s -output text, want error nil got %s", r.Name, err)
+			continue
+		}
+		for _, want := range r.Want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s -output text, want %s got:\n%s", r.Name, want, got)
+			}
+		}
+	}
+}
+
+func TestOutputEmpty(t *testing.T) {
+	got, err := commits(commitNoteDetails{}, OutputOptions{Output: OutputJSON})
+	if err != nil {
+		t.Fatalf("commits -output json, want error nil got %s", err)
+	}
+	if got != "[]\n" {
+		t.Errorf("commits -output json, want []\\n got %s", got)
+	}
+
+	got, err = commits(commitNoteDetails{}, OutputOptions{Output: OutputCSV})
+	if err != nil {
+		t.Fatalf("commits -output csv, want error nil got %s", err)
+	}
+	if want := "project,hash,date,author,subject,file,type,status,seconds\n"; got != want {
+		t.Errorf("commits -output csv, want %s got %s", want, got)
+	}
+
+	if _, err = commits(commitNoteDetails{}, OutputOptions{Output: "xml"}); err == nil {
+		t.Errorf("commits -output xml, want error got nil")
+	}
+}
+
+func TestOutputOptionsWarn(t *testing.T) {
+	// warnings are ignored without a handler
+	OutputOptions{}.warn("ignored")
+
+	got := []string{}
+	options := OutputOptions{Warn: func(msg string) { got = append(got, msg) }}
+	options.warn("Unable to read trusted keys for gtm, permission denied")
+	if len(got) != 1 || got[0] != "Unable to read trusted keys for gtm, permission denied" {
+		t.Errorf("warn(), want the warning passed to Warn got %+v", got)
+	}
+}
+
+func TestAuthors(t *testing.T) {
+	// uncommitted time has no author
+	got := testNotes()[1:].authors()
+	if len(got) != 2 {
+		t.Fatalf("authors(), want 2 authors got %d, %+v", len(got), got)
+	}
+	if got[0].Name != "Jane Doe" || got[0].Seconds != 3000 || got[0].Commits != 1 || got[0].Average() != 3000 {
+		t.Errorf("authors(), want Jane Doe 3000 seconds 1 commit got %+v", got[0])
+	}
+	if len(got[0].Files) != 2 || got[0].Files[0].Filename != "event/event.go" {
+		t.Errorf("authors(), want top file event/event.go got %+v", got[0].Files)
+	}
+	// emails are matched ignoring case and commits without time are not counted
+	if got[1].Name != "John Doe" || got[1].Seconds != 1800 || got[1].Commits != 1 {
+		t.Errorf("authors(), want John Doe 1800 seconds 1 commit got %+v", got[1])
+	}
+
+	// the same file in another project is a different file
+	notes := testNotes()[1:]
+	other := notes[0]
+	other.Project = "web"
+	other.Note = note.CommitNote{Files: []note.FileDetail{{SourceFile: "event/event.go", TimeSpent: 1200}}}
+	got = append(notes, other).authors()
+	want := []fileEntry{{Project: "gtm", Filename: "event/event.go", Seconds: 2700}, {Project: "web", Filename: "event/event.go", Seconds: 1200}}
+	if len(got[0].Files) != 3 || got[0].Files[0] != want[0] || got[0].Files[1] != want[1] {
+		t.Errorf("authors(), want top files %+v got %+v", want, got[0].Files)
+	}
+}
+
+func TestStatusOutputGolden(t *testing.T) {
+	n := testNotes()[1].Note
+	d, err := NewStatusData(n, OutputOptions{AppOff: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Project = "gtm"
+	d.Tags = []string{"work", "go"}
+
+	for _, output := range []string{OutputJSON, OutputCSV, OutputTSV} {
+		got, err := StatusOutput([]StatusData{d}, OutputOptions{Output: output})
+		if err != nil {
+			t.Errorf("status -output %s, want error nil got %s", output, err)
+			continue
+		}
+		checkGolden(t, "status."+output, got)
+	}
+}
+
+func TestHTMLGolden(t *testing.T) {
+	saveNow := util.Now
+	defer func() { util.Now = saveNow }()
+	util.Now = func() time.Time { return time.Date(2015, 7, 1, 9, 0, 0, 0, time.UTC) }
+
+	got, err := htmlReport(testNotes(), OutputOptions{Output: OutputHTML})
+	if err != nil {
+		t.Fatalf("htmlReport, want error nil got %s", err)
+	}
+	checkGolden(t, "report.html", got)
+
+	if strings.Contains(got, "ZgotmplZ") {
+		t.Errorf("htmlReport, want safe values got ZgotmplZ")
+	}
+}
+
+func TestMarkdownGolden(t *testing.T) {
+	reports := []struct {
+		Name   string
+		Report func(commitNoteDetails, OutputOptions) (string, error)
+	}{
+		{"commits", commits},
+		{"summary", commitSummary},
+		{"files", files},
+	}
+
+	for _, r := range reports {
+		got, err := r.Report(testNotes(), OutputOptions{Output: OutputMarkdown})
+		if err != nil {
+			t.Errorf("%s -output markdown, want error nil got %s", r.Name, err)
+			continue
+		}
+		checkGolden(t, r.Name+".md", got)
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	cells := []struct {
+		Value string
+		Want  string
+	}{
+		{"Fix *bold* `code` [link](x)", "Fix \\*bold\\* \\`code\\` \\[link\\](x)"},
+		{"<script>a & b</script> | c", "&lt;script&gt;a &amp; b&lt;/script&gt; \\| c"},
+	}
+	for _, tc := range cells {
+		if got := markdownCell(tc.Value); got != tc.Want {
+			t.Errorf("markdownCell(%s), want %s got %s", tc.Value, tc.Want, got)
+		}
+	}
+
+	codes := []struct {
+		Value string
+		Want  string
+	}{
+		{"event.go", "`event.go`"},
+		{"a`b.go", "``a`b.go``"},
+		{"`a``.go", "``` `a``.go ```"},
+		{"a|b.go", "`a\\|b.go`"},
+	}
+	for _, tc := range codes {
+		if got := markdownCode(tc.Value); got != tc.Want {
+			t.Errorf("markdownCode(%s), want %s got %s", tc.Value, tc.Want, got)
+		}
+	}
+}
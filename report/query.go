@@ -5,12 +5,18 @@
 package report
 
 import (
+	"crypto/ed25519"
 	"fmt"
+	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/git-time-metric/gtm/cache"
+	"github.com/git-time-metric/gtm/epoch"
 	"github.com/git-time-metric/gtm/note"
 	"github.com/git-time-metric/gtm/project"
 	"github.com/git-time-metric/gtm/scm"
@@ -21,66 +27,298 @@ const (
 	defaultDateFormat = "Mon Jan 02 15:04:05 2006 MST"
 )
 
-func retrieveNotes(projects []ProjectCommits, terminalOff, appOff, calcStats bool, dateFormat string) commitNoteDetails {
-	notes := commitNoteDetails{}
+// retrieveWorkers is the maximum number of projects whose notes are retrieved concurrently
+var retrieveWorkers = runtime.NumCPU()
 
+// retrieveNotes returns the notes for the projects' commits, newest first, projects are retrieved concurrently
+// and commits with the same time are in the order of the projects and their commits
+func retrieveNotes(projects []ProjectCommits, options OutputOptions, calcStats bool, dateFormat string) commitNoteDetails {
 	if dateFormat == "" {
 		dateFormat = defaultDateFormat
 	}
 
-	for _, p := range projects {
-		for _, c := range p.Commits {
+	workers := retrieveWorkers
+	if workers > len(projects) {
+		workers = len(projects)
+	}
+	if workers < 1 {
+		workers = 1
+	}
 
-			n, err := scm.ReadNote(c, project.NoteNameSpace, calcStats, p.Path)
-			if err != nil {
-				notes = append(notes, commitNoteDetail{})
-				continue
+	results := make([]commitNoteDetails, len(projects))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = retrieveProjectNotes(projects[i], options, calcStats, dateFormat)
 			}
+		}()
+	}
+	for i := range projects {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-			when := n.When.Format(dateFormat)
+	notes := commitNoteDetails{}
+	for _, r := range results {
+		notes = append(notes, r...)
+	}
+	sort.Stable(notes)
+	return notes
+}
 
-			var commitNote note.CommitNote
-			commitNote, err = note.UnMarshal(n.Note)
-			if err != nil {
-				commitNote = note.CommitNote{}
-			}
+// retrieveProjectNotes returns the notes for a project's commits, the repository is opened once
+func retrieveProjectNotes(p ProjectCommits, options OutputOptions, calcStats bool, dateFormat string) commitNoteDetails {
+	notes := commitNoteDetails{}
 
-			if terminalOff {
-				commitNote = commitNote.FilterOutTerminal()
-			}
-			if appOff {
-				commitNote = commitNote.FilterOutApp()
-			}
+	// full detail notes are kept in the local namespace when sharing reduced notes
+	cfg, err := project.LoadConfig(filepath.Join(p.Path, project.GTMDir))
+	if err != nil {
+		cfg = project.Config{}
+	}
+
+	tags, err := project.LoadTags(filepath.Join(p.Path, project.GTMDir))
+	if err != nil {
+		tags = []string{}
+	}
+
+	// author aliases from the project's .mailmap are overridden by the user's aliases
+	mailmap, err := scm.LoadMailmap(filepath.Join(p.Path, scm.MailmapFile))
+	if err != nil {
+		mailmap = scm.Mailmap{}
+	}
+	mailmap = mailmap.Merge(options.Mailmap)
+
+	var trustedKeys []ed25519.PublicKey
+	if options.Verify {
+		// missing key files are not an error, signed notes are then untrusted
+		trustedKeys, err = loadTrustedKeys(p.Path)
+		if err != nil {
+			util.Debug.Printf("Unable to read trusted keys for %s, %s", p.Path, err)
+			options.warn(fmt.Sprintf("Unable to read trusted keys for %s, %s", filepath.Base(p.Path), err))
+			trustedKeys = []ed25519.PublicKey{}
+		}
+	}
+
+	var (
+		reader     *scm.NoteReader
+		notesCache *cache.Notes
+	)
+	if len(p.Commits) > 0 {
+		if reader, err = scm.NewNoteReader(p.Path); err == nil {
+			defer reader.Free()
+			notesCache = openCache(p.Path, reader, cfg.KeepLocal, options.NoCache)
+			defer func() {
+				if err := notesCache.Save(); err != nil {
+					util.Debug.Printf("Unable to save note cache for %s, %s", p.Path, err)
+				}
+			}()
+		}
+	}
+
+	// branch names are only needed to find issue keys
+	branches := map[string]string{}
+	if options.IssuePattern != nil && len(p.Commits) > 0 {
+		if branches, err = scm.CommitBranches(p.Commits, p.Path); err != nil {
+			util.Debug.Printf("Unable to find branches for %s, %s", p.Path, err)
+			branches = map[string]string{}
+		}
+	}
+
+	for _, c := range p.Commits {
+		if reader == nil {
+			notes = append(notes, commitNoteDetail{})
+			continue
+		}
+
+		n, commitNote, err := readNote(reader, notesCache, c, cfg.KeepLocal, calcStats)
+		if err != nil {
+			notes = append(notes, commitNoteDetail{})
+			continue
+		}
 
-			id := n.ID
-			if len(id) > 7 {
-				id = id[:7]
+		signature := ""
+		if options.Verify {
+			signature, _ = note.Verify(n.Note, n.ID, trustedKeys)
+		}
+
+		commitNote = options.filterNote(commitNote)
+		if options.Files.IsSet() && commitNote.Total() == 0 {
+			// commits without time in the selected files are not reported
+			continue
+		}
+
+		if options.TimeSpent && options.DateRange.IsSet() {
+			commitNote = commitNote.Clip(options.DateRange, n.When)
+			if commitNote.Total() == 0 {
+				continue
 			}
+		}
+
+		// dates are reported in the time zone for the commit note
+		when := n.When.In(options.location(commitNote, n.When))
 
-			message := strings.TrimPrefix(n.Message, n.Summary)
-			message = strings.TrimSpace(message)
-
-			notes = append(notes,
-				commitNoteDetail{
-					Author:     n.Author,
-					Date:       when,
-					When:       n.When,
-					Hash:       id,
-					Subject:    n.Summary,
-					Message:    message,
-					Note:       commitNote,
-					Project:    filepath.Base(p.Path),
-					LineAdd:    fmt.Sprintf("+%d", n.Stats.Insertions),
-					LineDel:    fmt.Sprintf("-%d", n.Stats.Deletions),
-					LineDiff:   fmt.Sprintf("%d", n.Stats.Insertions-n.Stats.Deletions),
-					ChangeRate: fmt.Sprintf("%.0f", n.Stats.ChangeRatePerHour(commitNote.Total())),
-				})
+		id := n.ID
+		if len(id) > 7 {
+			id = id[:7]
 		}
+
+		author, email := mailmap.Lookup(n.Author, n.Email)
+
+		message := strings.TrimPrefix(n.Message, n.Summary)
+		message = strings.TrimSpace(message)
+
+		notes = append(notes,
+			commitNoteDetail{
+				Author:     author,
+				Email:      email,
+				Date:       when.Format(dateFormat),
+				When:       when,
+				ID:         n.ID,
+				Hash:       id,
+				Subject:    n.Summary,
+				Message:    message,
+				Note:       commitNote,
+				Project:    filepath.Base(p.Path),
+				Tags:       tags,
+				LineAdd:    fmt.Sprintf("+%d", n.Stats.Insertions),
+				LineDel:    fmt.Sprintf("-%d", n.Stats.Deletions),
+				LineDiff:   fmt.Sprintf("%d", n.Stats.Insertions-n.Stats.Deletions),
+				ChangeRate: fmt.Sprintf("%.0f", n.Stats.ChangeRatePerHour(commitNote.Total())),
+				Insertions: n.Stats.Insertions,
+				Deletions:  n.Stats.Deletions,
+				Signature:  signature,
+				Branch:     branches[n.ID],
+			})
+	}
+
+	if n, ok := pendingNote(p, options, tags, dateFormat); ok {
+		notes = append(notes, n)
 	}
-	sort.Sort(notes)
 	return notes
 }
 
+// openCache returns the project's note cache, nil if the cache can not be used or is turned off
+func openCache(projPath string, reader *scm.NoteReader, keepLocal, noCache bool) *cache.Notes {
+	if noCache || os.Getenv(cache.DisableEnv) != "" {
+		return nil
+	}
+	dir, err := cache.DirPath()
+	if err != nil {
+		return nil
+	}
+	notesCache, err := cache.Open(dir, projPath)
+	if err != nil {
+		return nil
+	}
+	nameSpaces := []string{project.NoteNameSpace}
+	if keepLocal {
+		nameSpaces = append(nameSpaces, project.LocalNoteNameSpace)
+	}
+	for _, ns := range nameSpaces {
+		if err := notesCache.Validate(ns, reader); err != nil {
+			return nil
+		}
+	}
+	return notesCache
+}
+
+// readNote returns a commit's note and the parsed note, full detail notes in the local namespace are used
+// if keepLocal is true. Parsed notes and commit stats are read from and saved to the cache.
+func readNote(reader *scm.NoteReader, notesCache *cache.Notes, commitID string, keepLocal, calcStats bool) (scm.CommitNote, note.CommitNote, error) {
+	n, err := reader.Read(commitID, project.NoteNameSpace, false)
+	if err != nil {
+		return scm.CommitNote{}, note.CommitNote{}, err
+	}
+	ns := project.NoteNameSpace
+	if keepLocal {
+		if local, err := reader.Read(commitID, project.LocalNoteNameSpace, false); err == nil && local.Note != "" {
+			n.Note, n.NoteID, ns = local.Note, local.NoteID, project.LocalNoteNameSpace
+		}
+	}
+
+	entry, cached := notesCache.Get(ns, n.ID, n.NoteID)
+	if !cached {
+		if entry.Note, err = note.UnMarshal(n.Note); err != nil {
+			entry.Note = note.CommitNote{}
+		}
+	}
+	if calcStats && !entry.HasStats {
+		if entry.Stats, err = reader.Stats(n.ID); err != nil {
+			return scm.CommitNote{}, note.CommitNote{}, err
+		}
+		entry.HasStats = true
+		cached = false
+	}
+	if !cached {
+		notesCache.Put(ns, n.ID, n.NoteID, entry)
+	}
+
+	if calcStats {
+		n.Stats = entry.Stats
+	}
+	return n, entry.Note, nil
+}
+
+const (
+	// pendingHash is shown in place of a commit hash for uncommitted time
+	pendingHash = "pending"
+	// pendingSubject is shown in place of a commit subject for uncommitted time
+	pendingSubject = "(uncommitted)"
+)
+
+// pendingNote returns a project's uncommitted time as of now, the time is clipped to the
+// reporting period and ok is false if there is no uncommitted time to report
+func pendingNote(p ProjectCommits, options OutputOptions, tags []string, dateFormat string) (commitNoteDetail, bool) {
+	now := util.Now()
+	n := options.filterNote(p.Pending)
+	if options.DateRange.IsSet() {
+		n = n.Clip(options.DateRange, now)
+	}
+	if n.Total() == 0 {
+		return commitNoteDetail{}, false
+	}
+
+	resolution := 0
+	if p.PendingByMinute {
+		resolution = epoch.WindowSize
+	}
+
+	when := now.In(options.location(n, now))
+	return commitNoteDetail{
+		Date:       when.Format(dateFormat),
+		When:       when,
+		Hash:       pendingHash,
+		Subject:    pendingSubject,
+		Note:       n,
+		Project:    filepath.Base(p.Path),
+		Tags:       tags,
+		LineAdd:    "+0",
+		LineDel:    "-0",
+		LineDiff:   "0",
+		ChangeRate: "0",
+		Pending:    true,
+		Resolution: resolution,
+	}, true
+}
+
+// loadTrustedKeys returns the keys trusted for signing notes for the project in projPath
+func loadTrustedKeys(projPath string) ([]ed25519.PublicKey, error) {
+	keyPath, err := project.SigningKeyPath()
+	if err != nil {
+		return nil, err
+	}
+	key, err := note.LoadSigningKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return note.LoadTrustedKeys(filepath.Join(projPath, project.TrustedKeysFile), key)
+}
+
 type commitNoteDetails []commitNoteDetail
 
 func (c commitNoteDetails) Len() int           { return len(c) }
@@ -97,17 +335,29 @@ func (c commitNoteDetails) Total() int {
 
 type commitNoteDetail struct {
 	Author     string
+	Email      string
 	Date       string
 	When       time.Time
+	ID         string
 	Hash       string
 	Subject    string
 	Project    string
+	Tags       []string
 	Message    string
 	Note       note.CommitNote
 	LineAdd    string
 	LineDel    string
 	LineDiff   string
 	ChangeRate string
+	Insertions int
+	Deletions  int
+	Signature  string
+	// Branch is the local branch for commits only on one branch, it is only set for the issues report
+	Branch string
+	// Pending is true for uncommitted time
+	Pending bool
+	// Resolution is the seconds covered by each epoch of the note's timelines, 0 is by hour
+	Resolution int
 }
 
 func (c commitNoteDetails) files() fileEntries {
@@ -133,9 +383,18 @@ func (c commitNoteDetails) files() fileEntries {
 
 type fileEntries []fileEntry
 
-func (f fileEntries) Len() int           { return len(f) }
-func (f fileEntries) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f fileEntries) Less(i, j int) bool { return f[i].Seconds < f[j].Seconds }
+func (f fileEntries) Len() int      { return len(f) }
+func (f fileEntries) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f fileEntries) Less(i, j int) bool {
+	if f[i].Seconds != f[j].Seconds {
+		return f[i].Seconds < f[j].Seconds
+	}
+	// ties are in alphabetical order when sorting in reverse
+	if f[i].Filename != f[j].Filename {
+		return f[i].Filename > f[j].Filename
+	}
+	return f[i].Project > f[j].Project
+}
 
 func (f fileEntries) Duration() string {
 	return util.FormatDuration(f.Total())
@@ -150,6 +409,8 @@ func (f fileEntries) Total() int {
 }
 
 type fileEntry struct {
+	// Project is set when files are reported across projects
+	Project  string
 	Filename string
 	Seconds  int
 }
@@ -170,6 +431,10 @@ func (f *fileEntry) IsApp() bool {
 	return project.AppEventFileContentRegex.MatchString(f.Filename)
 }
 
+func (f *fileEntry) IsHidden() bool {
+	return f.Filename == note.HiddenFile
+}
+
 // GetAppName returns the name of the App
 func (f *fileEntry) GetAppName() string {
 	name := project.AppEventFileContentRegex.FindStringSubmatch(f.Filename)[1]
@@ -0,0 +1,184 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/git-time-metric/gtm/cache"
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/scm"
+	"github.com/git-time-metric/gtm/util"
+	"github.com/libgit2/git2go"
+)
+
+func TestMain(m *testing.M) {
+	// tests must not write to the user's note cache
+	os.Setenv(cache.DisableEnv, "1")
+	os.Exit(m.Run())
+}
+
+func TestPendingNote(t *testing.T) {
+	saveNow := util.Now
+	defer func() { util.Now = saveNow }()
+	now := time.Date(2015, 6, 30, 17, 0, 0, 0, time.UTC)
+	util.Now = func() time.Time { return now }
+
+	p := ProjectCommits{
+		Path: "/src/gtm",
+		Pending: note.CommitNote{Files: []note.FileDetail{
+			{SourceFile: "main.go", TimeSpent: 600, Status: "m", Timeline: map[int64]int{
+				now.Add(-48 * time.Hour).Unix(): 240,
+				now.Add(-time.Hour).Unix():      360,
+			}},
+		}},
+	}
+
+	n, ok := pendingNote(p, OutputOptions{Location: time.UTC}, []string{"work"}, "")
+	if !ok || !n.Pending || n.Hash != pendingHash || n.Subject != pendingSubject || n.Project != "gtm" || n.Note.Total() != 600 {
+		t.Errorf("pendingNote(), want pending gtm entry with 600 seconds got %+v, %t", n, ok)
+	}
+	if !n.When.Equal(now) {
+		t.Errorf("pendingNote(), want when %s got %s", now, n.When)
+	}
+
+	// uncommitted time is clipped to the reporting period
+	options := OutputOptions{DateRange: util.TodayRange(time.UTC)}
+	n, ok = pendingNote(p, options, []string{}, "")
+	if !ok || n.Note.Total() != 360 {
+		t.Errorf("pendingNote() for today, want 360 seconds got %d, %t", n.Note.Total(), ok)
+	}
+
+	options = OutputOptions{DateRange: util.LastWeekRange(time.Sunday, time.UTC)}
+	if _, ok = pendingNote(p, options, []string{}, ""); ok {
+		t.Errorf("pendingNote() for last week, want ok false got true")
+	}
+
+	if _, ok = pendingNote(ProjectCommits{Path: "/src/gtm"}, OutputOptions{}, []string{}, ""); ok {
+		t.Errorf("pendingNote() without uncommitted time, want ok false got true")
+	}
+}
+
+func TestLimitNotes(t *testing.T) {
+	notes := commitNoteDetails{{Hash: pendingHash, Pending: true}, {Hash: "a"}, {Hash: "b"}}
+
+	got := OutputOptions{Limit: 1}.limitNotes(notes)
+	if len(got) != 2 || got[0].Hash != pendingHash || got[1].Hash != "a" {
+		t.Errorf("limitNotes(1), want [pending a] got %+v", got)
+	}
+	if got := (OutputOptions{}).limitNotes(notes); len(got) != 3 {
+		t.Errorf("limitNotes(0), want 3 notes got %d", len(got))
+	}
+}
+
+func TestRetrieveNotesOrder(t *testing.T) {
+	saveNow, saveWorkers := util.Now, retrieveWorkers
+	defer func() { util.Now, retrieveWorkers = saveNow, saveWorkers }()
+	now := time.Date(2015, 6, 30, 17, 0, 0, 0, time.UTC)
+	util.Now = func() time.Time { return now }
+	retrieveWorkers = 4
+
+	// uncommitted time for every project is at the same time so projects must stay in order
+	projects := []ProjectCommits{}
+	want := []string{}
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("proj%02d", i)
+		projects = append(projects, ProjectCommits{
+			Path:    filepath.Join(os.TempDir(), name),
+			Pending: note.CommitNote{Files: []note.FileDetail{{SourceFile: "main.go", TimeSpent: 60 + i}}},
+		})
+		want = append(want, name)
+	}
+
+	for run := 0; run < 10; run++ {
+		notes := retrieveNotes(projects, OutputOptions{}, false, "")
+		if len(notes) != len(want) {
+			t.Fatalf("retrieveNotes(), want %d notes got %d", len(want), len(notes))
+		}
+		for i, n := range notes {
+			if n.Project != want[i] {
+				t.Fatalf("retrieveNotes() run %d, want project %s at %d got %s", run, want[i], i, n.Project)
+			}
+		}
+	}
+}
+
+func BenchmarkRetrieveNotes(b *testing.B) {
+	projects := []ProjectCommits{}
+	for p := 0; p < 8; p++ {
+		repo := util.NewTestRepo(b, false)
+		defer repo.Remove()
+
+		commits := []string{}
+		for i := 0; i < 25; i++ {
+			file := fmt.Sprintf("file%d.go", i)
+			repo.SaveFile(file, "", "package main\n")
+			id := repo.Commit(repo.Stage(file))
+			n := note.CommitNote{Files: []note.FileDetail{{SourceFile: file, TimeSpent: 60, Timeline: map[int64]int{1435676400: 60}, Status: "m"}}}
+			util.CheckFatal(b, scm.CreateNote(note.Marshal(n), project.NoteNameSpace, repo.Workdir()))
+			commits = append(commits, id.String())
+		}
+		projects = append(projects, ProjectCommits{Path: repo.Workdir(), Commits: commits})
+	}
+
+	for _, workers := range []int{1, runtime.NumCPU()} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			saveWorkers := retrieveWorkers
+			defer func() { retrieveWorkers = saveWorkers }()
+			retrieveWorkers = workers
+
+			for i := 0; i < b.N; i++ {
+				if notes := retrieveNotes(projects, OutputOptions{}, true, ""); len(notes) != 8*25 {
+					b.Fatalf("retrieveNotes(), want %d notes got %d", 8*25, len(notes))
+				}
+			}
+		})
+	}
+}
+
+func TestRetrieveNotesCache(t *testing.T) {
+	home, err := ioutil.TempDir("", "gtm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	saveHome := project.GTMHomeDir
+	defer func() { project.GTMHomeDir = saveHome }()
+	project.GTMHomeDir = home
+	os.Unsetenv(cache.DisableEnv)
+	defer os.Setenv(cache.DisableEnv, "1")
+
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	repo.SaveFile("main.go", "", "package main\n")
+	id := repo.Commit(repo.Stage("main.go"))
+
+	saveNote := func(secs int, force bool) {
+		n := note.CommitNote{Files: []note.FileDetail{
+			{SourceFile: "main.go", TimeSpent: secs, Timeline: map[int64]int{1435676400: secs}, Status: "m"}}}
+		sig := &git.Signature{Name: "gtm", Email: "gtm@example.com", When: time.Now()}
+		_, err := repo.Repo().Notes.Create("refs/notes/"+project.NoteNameSpace, sig, sig, id, note.Marshal(n), force)
+		util.CheckFatal(t, err)
+	}
+	projects := []ProjectCommits{{Path: repo.Workdir(), Commits: []string{id.String()}}}
+
+	saveNote(60, false)
+	if got := retrieveNotes(projects, OutputOptions{}, false, "").Total(); got != 60 {
+		t.Errorf("retrieveNotes(), want 60 seconds got %d", got)
+	}
+
+	// the rewritten note is read instead of the cached note
+	saveNote(120, true)
+	if got := retrieveNotes(projects, OutputOptions{}, false, "").Total(); got != 120 {
+		t.Errorf("retrieveNotes() after rewriting the note, want 120 seconds got %d", got)
+	}
+}
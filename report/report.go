@@ -9,12 +9,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/git-time-metric/gtm/note"
 	"github.com/git-time-metric/gtm/project"
+	"github.com/git-time-metric/gtm/scm"
 	"github.com/git-time-metric/gtm/util"
 	isatty "github.com/mattn/go-isatty"
 )
@@ -25,12 +29,18 @@ var funcMap = template.FuncMap{
 	"LeftPad2Len":    util.LeftPad2Len,
 	"Percent":        util.Percent,
 	"Blocks":         BlockForVal,
+	"FormatHours":    formatHours,
+	"FormatMoney":    formatMoney,
 }
 
 // ProjectCommits contains a project's directory path and commit ids
 type ProjectCommits struct {
 	Path    string
 	Commits []string
+	// Pending is the project's uncommitted time, it is reported as an uncommitted entry if it has time spent
+	Pending note.CommitNote
+	// PendingByMinute is true if Pending's timelines are by minute instead of by hour, see metric.ProcessByMinute
+	PendingByMinute bool
 }
 
 // OutputOptions contains cli options for reporting
@@ -42,12 +52,103 @@ type OutputOptions struct {
 	AppOff       bool
 	Color        bool
 	Limit        int
+	Verify       bool
+	// NoCache reads and parses every note instead of using the cache, the cache is also off if GTM_NO_CACHE is set
+	NoCache bool
+	// Location is the time zone for dates and timelines, nil defaults to the system's time zone
+	Location *time.Location
+	// AuthorZone reports each commit in the time zone it was recorded in
+	AuthorZone bool
+	// Output is the output format [text|json|csv|tsv], blank defaults to text
+	Output string
+	// Mailmap maps author aliases in addition to each project's .mailmap
+	Mailmap scm.Mailmap
+	// Depth is the number of directory levels for the dirs report, 0 is no limit
+	Depth int
+	// DateRange is the reporting period, it limits the days of the timesheet report
+	DateRange util.DateRange
+	// TimeSpent clips commit notes to the time spent within DateRange,
+	// commits without time spent within DateRange are excluded
+	TimeSpent bool
+	// ByTag reports the timesheet by project tag instead of by project
+	ByTag bool
+	// Decimal reports the timesheet in decimal hours
+	Decimal bool
+	// Round rounds the time for each day of the timesheet to the nearest minutes
+	Round int
+	// Rates are the billing rates and rounding policy for invoices
+	Rates project.Rates
+	// GroupBy groups the summary and project reports by period [day|week|month|quarter|year]
+	GroupBy string
+	// WeekStart is the first day of the week for weekly periods, defaults to sunday
+	WeekStart time.Weekday
+	// Categories are the rules for classifying files in the categories report in addition to the defaults
+	Categories project.CategoryRules
+	// CompareRange is the earlier period that DateRange is compared to in the compare report
+	CompareRange util.DateRange
+	// Files selects the files of commit notes by path, status, kind and time spent
+	Files note.FileFilter
+	// IssuePattern finds issue keys in commit messages and branch names for the issues report
+	IssuePattern *regexp.Regexp
+	// SessionGap is the seconds without time spent that end a work session, 0 defaults to DefaultSessionGap
+	SessionGap int
+	// DeepWork is the seconds spent in a session of a single project for it to be deep work, 0 defaults to DefaultDeepWork
+	DeepWork int
+	// Warn is called with problems that do not stop the report, i.e. unreadable trusted keys, nil ignores them
+	Warn func(string)
 }
 
+// warnMutex serializes warnings from projects retrieved concurrently
+var warnMutex sync.Mutex
+
+// warn reports a problem that does not stop the report
+func (o OutputOptions) warn(msg string) {
+	if o.Warn == nil {
+		return
+	}
+	warnMutex.Lock()
+	defer warnMutex.Unlock()
+	o.Warn(msg)
+}
+
+// sessionGap returns the seconds without time spent that end a work session
+func (o OutputOptions) sessionGap() int {
+	if o.SessionGap > 0 {
+		return o.SessionGap
+	}
+	return DefaultSessionGap
+}
+
+// location returns the time zone to report a commit note in
+func (o OutputOptions) location(n note.CommitNote, when time.Time) *time.Location {
+	if o.AuthorZone {
+		if loc, ok := n.Location(); ok {
+			return loc
+		}
+		// the commit's time zone is the next best thing
+		return when.Location()
+	}
+	if o.Location != nil {
+		return o.Location
+	}
+	return time.Local
+}
+
+// limitNotes returns the most recent notes up to the limit, uncommitted time is not counted
 func (o OutputOptions) limitNotes(notes commitNoteDetails) commitNoteDetails {
-	ns := notes
-	if o.Limit > 0 && len(ns) > o.Limit {
-		ns = ns[0:o.Limit]
+	if o.Limit <= 0 {
+		return notes
+	}
+	ns := commitNoteDetails{}
+	cnt := 0
+	for _, n := range notes {
+		if !n.Pending {
+			if cnt == o.Limit {
+				continue
+			}
+			cnt++
+		}
+		ns = append(ns, n)
 	}
 	return ns
 }
@@ -56,12 +157,7 @@ func (o OutputOptions) limitNotes(notes commitNoteDetails) commitNoteDetails {
 func Status(n note.CommitNote, options OutputOptions, projPath ...string) (string, error) {
 	defer util.Profile()()
 
-	if options.TerminalOff {
-		n = n.FilterOutTerminal()
-	}
-	if options.AppOff {
-		n = n.FilterOutApp()
-	}
+	n = options.filterNote(n)
 
 	if options.TotalOnly {
 		if options.LongDuration {
@@ -70,21 +166,15 @@ func Status(n note.CommitNote, options OutputOptions, projPath ...string) (strin
 		return util.DurationStr(n.Total()), nil
 	}
 
-	projName := ""
-	tags := ""
-	if len(projPath) > 0 {
-		projName = filepath.Base(projPath[0])
-		tagList, err := project.LoadTags(filepath.Join(projPath[0], ".gtm"))
-		if err != nil {
-			return "", err
-		}
-		tags = strings.Join(tagList, ",")
+	projName, tagList, err := projectInfo(projPath...)
+	if err != nil {
+		return "", err
 	}
 
 	b := new(bytes.Buffer)
 	t := template.Must(template.New("Status").Funcs(funcMap).Parse(statusTpl))
 	cf := colorFormater{color: options.Color}
-	err := t.Execute(
+	err = t.Execute(
 		b,
 		struct {
 			ProjPath    []string
@@ -97,7 +187,7 @@ func Status(n note.CommitNote, options OutputOptions, projPath ...string) (strin
 			projName,
 			commitNoteDetail{Note: n},
 			cf.white(true),
-			tags,
+			strings.Join(tagList, ","),
 		})
 
 	if err != nil {
@@ -107,14 +197,77 @@ func Status(n note.CommitNote, options OutputOptions, projPath ...string) (strin
 	return b.String(), nil
 }
 
+// NewStatusData returns the pending time data for a project
+func NewStatusData(n note.CommitNote, options OutputOptions, projPath ...string) (StatusData, error) {
+	n = options.filterNote(n)
+
+	projName, tagList, err := projectInfo(projPath...)
+	if err != nil {
+		return StatusData{}, err
+	}
+
+	return StatusData{Project: projName, Tags: tagList, Seconds: n.Total(), Files: newFilesData(n.Files)}, nil
+}
+
+// StatusOutput returns the pending time data for projects in a structured output format
+func StatusOutput(data []StatusData, options OutputOptions) (string, error) {
+	return render(options.Output, statusData(data))
+}
+
+// filterNote removes the files excluded by the options before totals are computed
+func (o OutputOptions) filterNote(n note.CommitNote) note.CommitNote {
+	if o.TerminalOff {
+		n = n.FilterOutTerminal()
+	}
+	if o.AppOff {
+		n = n.FilterOutApp()
+	}
+	return n.Filter(o.Files)
+}
+
+func (o OutputOptions) isText() bool {
+	return o.Output == "" || o.Output == OutputText
+}
+
+// projectInfo returns the project's name and tags
+func projectInfo(projPath ...string) (string, []string, error) {
+	if len(projPath) == 0 {
+		return "", []string{}, nil
+	}
+	tagList, err := project.LoadTags(filepath.Join(projPath[0], ".gtm"))
+	if err != nil {
+		return "", []string{}, err
+	}
+	return filepath.Base(projPath[0]), tagList, nil
+}
+
 // CommitSummary returns the commit summary report
 func CommitSummary(projects []ProjectCommits, options OutputOptions) (string, error) {
-	notes := options.limitNotes(retrieveNotes(projects, options.TerminalOff, options.AppOff, false, "Mon Jan 02"))
+	return commitSummary(options.limitNotes(retrieveNotes(projects, options, false, "Mon Jan 02")), options)
+}
+
+func commitSummary(notes commitNoteDetails, options OutputOptions) (string, error) {
+	if options.Output == OutputMarkdown {
+		if len(notes) == 0 {
+			return "", nil
+		}
+		return markdown(summaryMarkdownTpl,
+			struct {
+				Days  summaryData
+				Total int
+			}{
+				notes.summaryData(options.GroupBy, options.WeekStart),
+				notes.Total(),
+			})
+	}
+	if !options.isText() {
+		return render(options.Output, notes.summaryData(options.GroupBy, options.WeekStart))
+	}
 	if len(notes) == 0 {
 		return "", nil
 	}
 
-	lines := commitSummaryBuilder{}.Build(notes)
+	lines := commitSummaryBuilder{GroupBy: options.GroupBy, WeekStart: options.WeekStart}.Build(notes)
 
 	b := new(bytes.Buffer)
 	t := template.Must(template.New("Commits").Funcs(funcMap).Parse(commitSummaryTpl))
@@ -138,16 +291,26 @@ func CommitSummary(projects []ProjectCommits, options OutputOptions) (string, er
 
 // ProjectSummary returns the project summary report
 func ProjectSummary(projects []ProjectCommits, options OutputOptions) (string, error) {
-	notes := options.limitNotes(retrieveNotes(projects, options.TerminalOff, options.AppOff, false, "Mon Jan 02"))
-	if len(notes) == 0 {
-		return "", nil
-	}
+	return projectSummary(options.limitNotes(retrieveNotes(projects, options, false, "Mon Jan 02")), options)
+}
 
+func projectSummary(notes commitNoteDetails, options OutputOptions) (string, error) {
 	projectTotals := map[string]int{}
 	for _, n := range notes {
 		projectTotals[n.Project] += n.Note.Total()
 	}
 
+	if options.GroupBy != "" {
+		return projectPeriodsReport(notes, options, projectTotals)
+	}
+
+	if !options.isText() {
+		return render(options.Output, newProjectsData(projectTotals))
+	}
+	if len(notes) == 0 {
+		return "", nil
+	}
+
 	b := new(bytes.Buffer)
 	t := template.Must(template.New("ProjectSummary").Funcs(funcMap).Parse(projectTotalsTpl))
 	cf := colorFormater{color: options.Color}
@@ -168,9 +331,58 @@ func ProjectSummary(projects []ProjectCommits, options OutputOptions) (string, e
 	return b.String(), nil
 }
 
+func projectPeriodsReport(notes commitNoteDetails, options OutputOptions, projectTotals map[string]int) (string, error) {
+	periods := notes.projectPeriods(options.GroupBy, options.WeekStart)
+
+	if !options.isText() {
+		return render(options.Output, periods.periodProjectsData())
+	}
+	if len(notes) == 0 {
+		return "", nil
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("ProjectPeriods").Funcs(funcMap).Parse(projectPeriodsTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Periods    projectPeriods
+			Projects   projectsData
+			Total      int
+			BoldFormat string
+		}{
+			periods,
+			newProjectsData(projectTotals),
+			notes.Total(),
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
 // Commits returns the commits report
 func Commits(projects []ProjectCommits, options OutputOptions) (string, error) {
-	notes := options.limitNotes(retrieveNotes(projects, options.TerminalOff, options.AppOff, true, ""))
+	return commits(options.limitNotes(retrieveNotes(projects, options, true, "")), options)
+}
+
+// CommitList returns the commits with their time data, newest first
+func CommitList(projects []ProjectCommits, options OutputOptions) ([]CommitData, error) {
+	return options.limitNotes(retrieveNotes(projects, options, true, "")).commitsData(), nil
+}
+
+func commits(notes commitNoteDetails, options OutputOptions) (string, error) {
+	if options.Output == OutputMarkdown {
+		if len(notes) == 0 {
+			return "", nil
+		}
+		return markdown(commitsMarkdownTpl, struct{ Notes commitNoteDetails }{notes})
+	}
+	if !options.isText() {
+		return render(options.Output, notes.commitsData())
+	}
 	if len(notes) == 0 {
 		return "", nil
 	}
@@ -199,17 +411,22 @@ func Commits(projects []ProjectCommits, options OutputOptions) (string, error) {
 
 // Timeline returns the time spent by hour
 func Timeline(projects []ProjectCommits, options OutputOptions) (string, error) {
-	notes := options.limitNotes(retrieveNotes(projects, options.TerminalOff, options.AppOff, false, ""))
-	if len(notes) == 0 {
-		return "", nil
-	}
+	return timeline(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
 
+func timeline(notes commitNoteDetails, options OutputOptions) (string, error) {
 	timeline, err := notes.timeline()
-
 	if err != nil {
 		return "", err
 	}
 
+	if !options.isText() {
+		return render(options.Output, timeline.timelineData())
+	}
+	if len(notes) == 0 {
+		return "", nil
+	}
+
 	b := new(bytes.Buffer)
 	t := template.Must(template.New("Timeline").Funcs(funcMap).Parse(timelineTpl))
 	cf := colorFormater{color: options.Color}
@@ -232,17 +449,22 @@ func Timeline(projects []ProjectCommits, options OutputOptions) (string, error)
 
 // TimelineCommits returns the number commits by hour
 func TimelineCommits(projects []ProjectCommits, options OutputOptions) (string, error) {
-	notes := options.limitNotes(retrieveNotes(projects, options.TerminalOff, options.AppOff, false, ""))
-	if len(notes) == 0 {
-		return "", nil
-	}
+	return timelineCommits(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
 
+func timelineCommits(notes commitNoteDetails, options OutputOptions) (string, error) {
 	timeline, err := notes.timelineCommits()
-
 	if err != nil {
 		return "", err
 	}
 
+	if !options.isText() {
+		return render(options.Output, timeline.timelineCommitData())
+	}
+	if len(notes) == 0 {
+		return "", nil
+	}
+
 	b := new(bytes.Buffer)
 	t := template.Must(template.New("Timeline").Funcs(funcMap).Parse(timelineCommitTpl))
 	cf := colorFormater{color: options.Color}
@@ -265,7 +487,19 @@ func TimelineCommits(projects []ProjectCommits, options OutputOptions) (string,
 
 // Files returns the files report
 func Files(projects []ProjectCommits, options OutputOptions) (string, error) {
-	notes := options.limitNotes(retrieveNotes(projects, options.TerminalOff, options.AppOff, false, ""))
+	return files(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func files(notes commitNoteDetails, options OutputOptions) (string, error) {
+	if options.Output == OutputMarkdown {
+		if len(notes) == 0 {
+			return "", nil
+		}
+		return markdown(filesMarkdownTpl, struct{ Files fileEntries }{notes.files()})
+	}
+	if !options.isText() {
+		return render(options.Output, notes.files().filesData())
+	}
 	if len(notes) == 0 {
 		return "", nil
 	}
@@ -287,6 +521,330 @@ func Files(projects []ProjectCommits, options OutputOptions) (string, error) {
 
 }
 
+// Dirs returns the time spent by directory
+func Dirs(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return dirs(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func dirs(notes commitNoteDetails, options OutputOptions) (string, error) {
+	if !options.isText() {
+		return render(options.Output, notes.dirs(options.Depth).dirsData())
+	}
+	if len(notes) == 0 {
+		return "", nil
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Dirs").Funcs(funcMap).Parse(dirsTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Dirs       dirEntries
+			BoldFormat string
+		}{
+			notes.dirs(options.Depth),
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Categories returns the time spent by file category and language
+func Categories(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return categories(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func categories(notes commitNoteDetails, options OutputOptions) (string, error) {
+	breakdown := notes.categories(options.Categories)
+	if !options.isText() {
+		return render(options.Output, breakdown.categoriesData())
+	}
+	if len(notes) == 0 {
+		return "", nil
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Categories").Funcs(funcMap).Parse(categoriesTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Categories categoryBreakdown
+			BoldFormat string
+		}{
+			breakdown,
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Issues returns the time spent by issue key found in commit messages and branch names
+func Issues(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return issues(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func issues(notes commitNoteDetails, options OutputOptions) (string, error) {
+	re := options.IssuePattern
+	if re == nil {
+		re = regexp.MustCompile(DefaultIssuePattern)
+	}
+	breakdown := notes.issues(re)
+	if !options.isText() {
+		return render(options.Output, breakdown.issuesData())
+	}
+	if breakdown.Total() == 0 {
+		return "", nil
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Issues").Funcs(funcMap).Parse(issuesTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Issues     issueBreakdown
+			BoldFormat string
+		}{
+			breakdown,
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Sessions returns the work sessions reconstructed from the commits' timelines by day,
+// with the largest idle gaps between the sessions of each day
+func Sessions(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return sessionsReport(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func sessionsReport(notes commitNoteDetails, options OutputOptions) (string, error) {
+	// sessions span commits so they are reported in options.Location even when reporting in the author's time zone
+	loc := options.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	days := notes.sessions(options.sessionGap()).days(loc)
+	if !options.isText() {
+		return render(options.Output, newSessionsData(days))
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Sessions").Funcs(funcMap).Parse(sessionsTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Days       []sessionDay
+			BoldFormat string
+		}{
+			days,
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Focus returns the project and file switches, deep work and a focus score by day from the sessions
+// reconstructed from the commits' timelines
+func Focus(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return focus(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func focus(notes commitNoteDetails, options OutputOptions) (string, error) {
+	deepWork := options.DeepWork
+	if deepWork <= 0 {
+		deepWork = DefaultDeepWork
+	}
+	// sessions span commits so they are reported in options.Location even when reporting in the author's time zone
+	loc := options.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	days := notes.sessions(options.sessionGap()).focus(loc, deepWork)
+	if !options.isText() {
+		return render(options.Output, newFocusData(days))
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Focus").Funcs(funcMap).Parse(focusTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Days       focusDays
+			BoldFormat string
+		}{
+			days,
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Compare returns the time spent in projects, by authors and in file categories within options.DateRange
+// compared to options.CompareRange, the current and previous commits are selected for each period
+func Compare(current, previous []ProjectCommits, options OutputOptions) (string, error) {
+	previousOptions := options
+	previousOptions.DateRange = options.CompareRange
+	return compareReport(
+		retrieveNotes(current, options, false, ""),
+		retrieveNotes(previous, previousOptions, false, ""),
+		options)
+}
+
+func compareReport(current, previous commitNoteDetails, options OutputOptions) (string, error) {
+	c := compare(current, previous, options.Categories)
+	if !options.isText() {
+		return render(options.Output, c.compareData(options.DateRange, options.CompareRange))
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Compare").Funcs(funcMap).Parse(compareTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Current    util.DateRange
+			Previous   util.DateRange
+			Comparison comparison
+			BoldFormat string
+		}{
+			options.DateRange,
+			options.CompareRange,
+			c,
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Timesheet returns the time spent by day for each project or tag
+func Timesheet(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return timesheetReport(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func timesheetReport(notes commitNoteDetails, options OutputOptions) (string, error) {
+	sheet := notes.timesheet(options.ByTag, options.DateRange, options.Round)
+	if !options.isText() {
+		return render(options.Output, sheet.timesheetData())
+	}
+	if len(sheet.Rows) == 0 {
+		return "", nil
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Timesheet").Funcs(funcMap).Parse(timesheetTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Sheet      timesheet
+			Width      int
+			Decimal    bool
+			BoldFormat string
+		}{
+			sheet,
+			sheet.NameWidth(),
+			options.Decimal,
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Invoice returns the billable time and amounts by project and commit subject
+func Invoice(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return invoiceReport(retrieveNotes(projects, options, false, ""), options)
+}
+
+func invoiceReport(notes commitNoteDetails, options OutputOptions) (string, error) {
+	inv := notes.invoice(options.Rates)
+
+	var from, to string
+	if !options.DateRange.Start.IsZero() {
+		from = options.DateRange.Start.Format("2006-01-02")
+	}
+	if !options.DateRange.End.IsZero() {
+		to = options.DateRange.End.Format("2006-01-02")
+	}
+
+	if !options.isText() {
+		return render(options.Output, inv.invoiceData(from, to))
+	}
+	if len(inv.Projects) == 0 {
+		return "", nil
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Invoice").Funcs(funcMap).Parse(invoiceTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			From       string
+			To         string
+			Invoice    invoice
+			BoldFormat string
+		}{
+			from,
+			to,
+			inv,
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Authors returns the time spent by author
+func Authors(projects []ProjectCommits, options OutputOptions) (string, error) {
+	return authors(options.limitNotes(retrieveNotes(projects, options, false, "")), options)
+}
+
+func authors(notes commitNoteDetails, options OutputOptions) (string, error) {
+	if !options.isText() {
+		return render(options.Output, notes.authors().authorsData())
+	}
+	if len(notes) == 0 {
+		return "", nil
+	}
+
+	b := new(bytes.Buffer)
+	t := template.Must(template.New("Authors").Funcs(funcMap).Parse(authorsTpl))
+	cf := colorFormater{color: options.Color}
+	err := t.Execute(
+		b,
+		struct {
+			Authors    authorEntries
+			BoldFormat string
+		}{
+			notes.authors(),
+			cf.white(true),
+		})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
 type colorFormater struct {
 	color bool
 }
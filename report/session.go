@@ -0,0 +1,280 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSessionGap is the seconds without time spent that end a work session
+	DefaultSessionGap = 15 * 60
+	// noteResolution is the seconds covered by each epoch of a commit note's timeline, notes are downsampled to hours
+	noteResolution = 3600
+	// sessionDayGaps is the number of idle gaps reported for each day
+	sessionDayGaps = 3
+)
+
+// sessionCommit is a commit with time spent in a session
+type sessionCommit struct {
+	Project string
+	Hash    string
+	Subject string
+	Seconds int
+	Pending bool
+}
+
+// sessionFile is the time spent on a file in a session
+type sessionFile struct {
+	Project    string
+	SourceFile string
+	Seconds    int
+}
+
+// sessionBucket is the time spent by project and by file in a timeline bucket of a session,
+// files are keyed by project and path
+type sessionBucket struct {
+	Epoch    int64
+	Projects map[string]int
+	Files    map[string]int
+}
+
+// session is a contiguous period of work reconstructed from timelines
+type session struct {
+	Start    time.Time
+	End      time.Time
+	Seconds  int
+	Projects []string
+	Commits  []sessionCommit
+	Files    []sessionFile
+	// Buckets are the session's timeline buckets ordered by epoch
+	Buckets []sessionBucket
+}
+
+// Title returns the session's projects with the subjects of their commits, i.e. gtm: Add events, Fix quotes
+func (s session) Title() string {
+	subjects := map[string][]string{}
+	for _, c := range s.Commits {
+		subjects[c.Project] = append(subjects[c.Project], c.Subject)
+	}
+	titles := []string{}
+	for _, p := range s.Projects {
+		titles = append(titles, p+": "+strings.Join(subjects[p], ", "))
+	}
+	return strings.Join(titles, "; ")
+}
+
+// ProjectList returns the session's projects
+func (s session) ProjectList() string {
+	return strings.Join(s.Projects, ", ")
+}
+
+type sessions []session
+
+// Total returns the time spent in the sessions
+func (s sessions) Total() int {
+	total := 0
+	for _, x := range s {
+		total += x.Seconds
+	}
+	return total
+}
+
+// resolution returns the seconds covered by each epoch of the note's timelines
+func (n commitNoteDetail) resolution() int64 {
+	if n.Resolution > 0 {
+		return int64(n.Resolution)
+	}
+	return noteResolution
+}
+
+// sessionActivity is the time spent on a file of a note in a timeline bucket
+type sessionActivity struct {
+	epoch int64
+	size  int64
+	secs  int
+	note  int
+	file  string
+}
+
+// sessions returns the work sessions in the notes' timelines ordered by start, adjacent timeline buckets
+// are merged and a session ends when more than gap seconds pass without time spent. The idle time between
+// buckets is measured from the end of a bucket, a gap under an hour only ends a session of hourly buckets
+// at an hour without time spent and applies by minute to timelines by minute.
+// Timelines only record the time spent within each bucket, so the time in a session's first bucket is
+// assumed to be just before the next bucket and the time in the other buckets at the start of the bucket.
+func (c commitNoteDetails) sessions(gap int) sessions {
+	acts := []sessionActivity{}
+	for i, n := range c {
+		for _, f := range n.Note.Files {
+			for epoch, secs := range f.Timeline {
+				if secs <= 0 {
+					continue
+				}
+				acts = append(acts,
+					sessionActivity{epoch: epoch, size: n.resolution(), secs: secs, note: i, file: f.SourceFile})
+			}
+		}
+	}
+	sort.Slice(acts, func(i, j int) bool {
+		if acts[i].epoch != acts[j].epoch {
+			return acts[i].epoch < acts[j].epoch
+		}
+		if acts[i].note != acts[j].note {
+			return acts[i].note < acts[j].note
+		}
+		return acts[i].file < acts[j].file
+	})
+
+	result := sessions{}
+	group := []sessionActivity{}
+	var end int64
+	for _, a := range acts {
+		if len(group) > 0 && a.epoch-end > int64(gap) {
+			result = append(result, c.newSession(group))
+			group = []sessionActivity{}
+		}
+		if len(group) == 0 || a.epoch+a.size > end {
+			end = a.epoch + a.size
+		}
+		group = append(group, a)
+	}
+	if len(group) > 0 {
+		result = append(result, c.newSession(group))
+	}
+	return result
+}
+
+// newSession returns the session for activity ordered by epoch
+func (c commitNoteDetails) newSession(acts []sessionActivity) session {
+	s := session{Projects: []string{}, Commits: []sessionCommit{}, Files: []sessionFile{}, Buckets: []sessionBucket{}}
+
+	buckets := map[int64]int64{}
+	// commits are indexes into s.Commits by note
+	commits := map[int]int{}
+	projects := map[string]bool{}
+	files := map[string]*sessionFile{}
+	for _, a := range acts {
+		buckets[a.epoch] += int64(a.secs)
+		s.Seconds += a.secs
+
+		n := c[a.note]
+		if len(s.Buckets) == 0 || s.Buckets[len(s.Buckets)-1].Epoch != a.epoch {
+			s.Buckets = append(s.Buckets, sessionBucket{Epoch: a.epoch, Projects: map[string]int{}, Files: map[string]int{}})
+		}
+		b := s.Buckets[len(s.Buckets)-1]
+		b.Projects[n.Project] += a.secs
+
+		if _, ok := commits[a.note]; !ok {
+			commits[a.note] = len(s.Commits)
+			s.Commits = append(s.Commits, sessionCommit{Project: n.Project, Hash: n.Hash, Subject: n.Subject, Pending: n.Pending})
+		}
+		s.Commits[commits[a.note]].Seconds += a.secs
+		if !projects[n.Project] {
+			projects[n.Project] = true
+			s.Projects = append(s.Projects, n.Project)
+		}
+		key := n.Project + "\x00" + a.file
+		f, ok := files[key]
+		if !ok {
+			f = &sessionFile{Project: n.Project, SourceFile: a.file}
+			files[key] = f
+		}
+		f.Seconds += a.secs
+		b.Files[key] += a.secs
+	}
+	sort.Strings(s.Projects)
+	for _, f := range files {
+		s.Files = append(s.Files, *f)
+	}
+	sort.Slice(s.Files, func(i, j int) bool {
+		a, b := s.Files[i], s.Files[j]
+		if a.Seconds != b.Seconds {
+			return a.Seconds > b.Seconds
+		}
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		return a.SourceFile < b.SourceFile
+	})
+
+	busy := func(a sessionActivity) int64 {
+		if buckets[a.epoch] > a.size {
+			return a.size
+		}
+		return buckets[a.epoch]
+	}
+	first := acts[0]
+	start := first.epoch
+	var end int64
+	placed := false
+	for _, a := range acts {
+		if !placed && a.epoch != first.epoch {
+			// the first bucket's time is just before the next bucket, which is within it for a finer resolution
+			next := first.epoch + first.size
+			if a.epoch < next {
+				next = a.epoch
+			}
+			if start = next - busy(first); start < first.epoch {
+				start = first.epoch
+			}
+			placed = true
+		}
+		if e := a.epoch + busy(a); e > end {
+			end = e
+		}
+	}
+	if end < start {
+		end = start
+	}
+	s.Start, s.End = time.Unix(start, 0), time.Unix(end, 0)
+	return s
+}
+
+// sessionGap is the idle time between sessions
+type sessionGap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Seconds returns the length of the gap
+func (g sessionGap) Seconds() int {
+	return int(g.End.Sub(g.Start).Seconds())
+}
+
+// sessionDay is the sessions starting on a day with the day's largest idle gaps between sessions
+type sessionDay struct {
+	Date     time.Time
+	Sessions sessions
+	Gaps     []sessionGap
+}
+
+// days returns the sessions by the day they start in loc, times are in loc
+func (s sessions) days(loc *time.Location) []sessionDay {
+	days := []sessionDay{}
+	for _, x := range s {
+		x.Start, x.End = x.Start.In(loc), x.End.In(loc)
+		date := dayStart(x.Start)
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, sessionDay{Date: date, Sessions: sessions{}, Gaps: []sessionGap{}})
+		}
+		d := &days[len(days)-1]
+		if len(d.Sessions) > 0 {
+			d.Gaps = append(d.Gaps, sessionGap{Start: d.Sessions[len(d.Sessions)-1].End, End: x.Start})
+		}
+		d.Sessions = append(d.Sessions, x)
+	}
+
+	for i := range days {
+		gaps := days[i].Gaps
+		sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].Seconds() > gaps[j].Seconds() })
+		if len(gaps) > sessionDayGaps {
+			days[i].Gaps = gaps[:sessionDayGaps]
+		}
+	}
+	return days
+}
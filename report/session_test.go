@@ -0,0 +1,148 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/util"
+)
+
+func TestSessions(t *testing.T) {
+	s := testNotes().sessions(DefaultSessionGap)
+	if len(s) != 3 {
+		t.Fatalf("sessions(), want 3 sessions got %+v", s)
+	}
+
+	// the first session's time in its first hour is at the end of the hour
+	first := s[0]
+	if want := time.Date(2015, 6, 30, 15, 30, 0, 0, time.UTC); !first.Start.Equal(want) {
+		t.Errorf("sessions(), want first session to start at %s got %s", want, first.Start.UTC())
+	}
+	if want := time.Date(2015, 6, 30, 16, 30, 0, 0, time.UTC); !first.End.Equal(want) {
+		t.Errorf("sessions(), want first session to end at %s got %s", want, first.End.UTC())
+	}
+	if first.Seconds != 3600 || len(first.Commits) != 2 || len(first.Files) != 3 {
+		t.Errorf("sessions(), want first session with 3600 seconds, 2 commits and 3 files got %+v", first)
+	}
+	if want := `gtm: PROJ-12 Add event handling; web: Fix "quoted", comma subject`; first.Title() != want {
+		t.Errorf("session.Title(), want %s got %s", want, first.Title())
+	}
+
+	if second := s[1]; second.Seconds != 600 || second.End.Sub(second.Start) != 10*time.Minute {
+		t.Errorf("sessions(), want second session of 10 minutes got %+v", second)
+	}
+	// uncommitted time is by minute
+	if third := s[2]; third.Seconds != 3900 || third.End.Sub(third.Start) != 65*time.Minute {
+		t.Errorf("sessions(), want third session of 65 minutes got %+v", third)
+	}
+
+	// a gap longer than the idle hour merges the sessions
+	if s := testNotes().sessions(3 * 3600); len(s) != 1 || s.Total() != 8100 {
+		t.Errorf("sessions(3h), want 1 session with 8100 seconds got %+v", s)
+	}
+}
+
+func TestSessionDays(t *testing.T) {
+	notes := testNotes()
+	// a session on the next day
+	notes[3].Note.Files = []note.FileDetail{
+		{SourceFile: "README", TimeSpent: 300, Timeline: map[int64]int{1435734000: 300}, Status: "m"}}
+
+	days := notes.sessions(DefaultSessionGap).days(time.UTC)
+	if len(days) != 2 || len(days[0].Sessions) != 3 || len(days[1].Sessions) != 1 {
+		t.Fatalf("days(), want 2 days with 3 and 1 sessions got %+v", days)
+	}
+	if want := time.Date(2015, 6, 30, 0, 0, 0, 0, time.UTC); !days[0].Date.Equal(want) {
+		t.Errorf("days(), want first day %s got %s", want, days[0].Date)
+	}
+	if gaps := days[0].Gaps; len(gaps) != 2 || gaps[0].Seconds() != 9000 || gaps[1].Seconds() != 6600 {
+		t.Errorf("days(), want idle gaps of 9000 and 6600 seconds on the first day got %+v", gaps)
+	}
+	if len(days[1].Gaps) != 0 {
+		t.Errorf("days(), want no idle gaps on the second day got %+v", days[1].Gaps)
+	}
+
+	// sessions are on the day they start in the time zone and the longest gap is first
+	zone := time.FixedZone("-1000", -10*3600)
+	days = notes.sessions(DefaultSessionGap).days(zone)
+	if len(days) != 1 || len(days[0].Sessions) != 4 {
+		t.Fatalf("days(-1000), want 1 day with 4 sessions got %+v", days)
+	}
+	if gaps := days[0].Gaps; len(gaps) != 3 || gaps[0].Seconds() != 32100 || gaps[1].Seconds() != 9000 || gaps[2].Seconds() != 6600 {
+		t.Errorf("days(-1000), want idle gaps of 32100, 9000 and 6600 seconds got %+v", gaps)
+	}
+}
+
+func TestExportICS(t *testing.T) {
+	saveNow := util.Now
+	defer func() { util.Now = saveNow }()
+	util.Now = func() time.Time { return time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC) }
+
+	got, err := export(testNotes(), OutputOptions{Output: OutputICS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "sessions.ics", got)
+
+	if _, err := export(testNotes(), OutputOptions{Output: OutputJSON}); err == nil {
+		t.Errorf("export -output json, want error got nil")
+	}
+}
+
+func TestICSFold(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 40)
+	folded := icsFold(line)
+	for _, l := range strings.Split(folded, "\r\n") {
+		if len(l) > icsLineLength {
+			t.Errorf("icsFold(), want lines of at most %d octets got %d", icsLineLength, len(l))
+		}
+	}
+	if unfolded := strings.Replace(folded, "\r\n ", "", -1); unfolded != line {
+		t.Errorf("icsFold(), want %s unfolded got %s", line, unfolded)
+	}
+}
+
+func TestSessionsMixedResolution(t *testing.T) {
+	// committed time by hour and uncommitted time by minute in the same hour
+	notes := commitNoteDetails{
+		{Project: "gtm", Hash: "0123456", Subject: "Add event handling", Note: note.CommitNote{
+			Files: []note.FileDetail{{SourceFile: "event.go", TimeSpent: 60, Timeline: map[int64]int{1458496800: 60}}}}},
+		{Project: "gtm", Hash: pendingHash, Subject: pendingSubject, Pending: true, Resolution: 60, Note: note.CommitNote{
+			Files: []note.FileDetail{{SourceFile: "event_test.go", TimeSpent: 60, Timeline: map[int64]int{1458496860: 60}}}}},
+	}
+
+	s := notes.sessions(DefaultSessionGap)
+	if len(s) != 1 || s[0].Seconds != 120 {
+		t.Fatalf("sessions(), want 1 session with 120 seconds got %+v", s)
+	}
+	if !s[0].Start.Equal(time.Unix(1458496800, 0)) || !s[0].End.Equal(time.Unix(1458496920, 0)) {
+		t.Errorf("sessions(), want session from 18:00 to 18:02 UTC got %s to %s", s[0].Start.UTC(), s[0].End.UTC())
+	}
+}
+
+func TestSessionsGapByMinute(t *testing.T) {
+	// uncommitted time by minute from 18:00 to 18:10 and from 18:40 to 18:50
+	timeline := map[int64]int{}
+	for m := int64(0); m < 50; m++ {
+		if m < 10 || m >= 40 {
+			timeline[1458496800+m*60] = 60
+		}
+	}
+	notes := commitNoteDetails{
+		{Project: "gtm", Hash: pendingHash, Subject: pendingSubject, Pending: true, Resolution: 60, Note: note.CommitNote{
+			Files: []note.FileDetail{{SourceFile: "event.go", TimeSpent: 1200, Timeline: timeline}}}},
+	}
+
+	if s := notes.sessions(15 * 60); len(s) != 2 {
+		t.Errorf("sessions(15m), want 2 sessions got %+v", s)
+	}
+	if s := notes.sessions(45 * 60); len(s) != 1 || s.Total() != 1200 {
+		t.Errorf("sessions(45m), want 1 session with 1200 seconds got %+v", s)
+	}
+}
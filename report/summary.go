@@ -4,10 +4,17 @@
 
 package report
 
+import (
+	"time"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
 type commitSummaryLine struct {
 	StartGroup bool
 	EndGroup   bool
 	CommitLine bool
+	GrandTotal bool
 	Date       string
 	Subject    string
 	Project    string
@@ -15,22 +22,68 @@ type commitSummaryLine struct {
 }
 
 type commitSummaryBuilder struct {
+	// GroupBy is the period to group commits by, blank groups by the commit's date
+	GroupBy string
+	// WeekStart is the first day of the week when grouping by week
+	WeekStart time.Weekday
 }
 
 func (c commitSummaryBuilder) Build(notes commitNoteDetails) []commitSummaryLine {
+	group := func(n commitNoteDetail) string {
+		if c.GroupBy == "" {
+			return n.Date
+		}
+		return util.PeriodLabel(c.GroupBy, n.When, c.WeekStart)
+	}
+
 	total := 0
 	lines := []commitSummaryLine{}
 	for idx, n := range notes {
-		if idx == 0 || notes[idx].Date != notes[idx-1].Date {
+		if idx == 0 || group(notes[idx]) != group(notes[idx-1]) {
 			if idx != 0 {
 				lines = append(lines, commitSummaryLine{EndGroup: true, Total: total})
 			}
 			total = 0
-			lines = append(lines, commitSummaryLine{StartGroup: true, Date: n.Date})
+			lines = append(lines, commitSummaryLine{StartGroup: true, Date: group(n)})
 		}
 		lines = append(lines, commitSummaryLine{CommitLine: true, Subject: n.Subject, Project: n.Project, Total: n.Note.Total()})
 		total += n.Note.Total()
 	}
 	lines = append(lines, commitSummaryLine{EndGroup: true, Total: total})
+	if c.GroupBy != "" {
+		lines = append(lines, commitSummaryLine{GrandTotal: true, Total: notes.Total()})
+	}
 	return lines
 }
+
+// projectPeriod is the time spent in projects for a period
+type projectPeriod struct {
+	Label    string
+	Start    time.Time
+	Projects projectsData
+	Total    int
+}
+
+type projectPeriods []projectPeriod
+
+// projectPeriods returns the time spent in projects by period, newest period first
+func (c commitNoteDetails) projectPeriods(groupBy string, weekStart time.Weekday) projectPeriods {
+	periods := projectPeriods{}
+	totals := map[string]int{}
+	for idx, n := range c {
+		start := util.PeriodStart(groupBy, n.When, weekStart)
+		if idx == 0 || !start.Equal(periods[len(periods)-1].Start) {
+			if idx != 0 {
+				periods[len(periods)-1].Projects = newProjectsData(totals)
+			}
+			totals = map[string]int{}
+			periods = append(periods, projectPeriod{Label: util.PeriodLabel(groupBy, n.When, weekStart), Start: start})
+		}
+		totals[n.Project] += n.Note.Total()
+		periods[len(periods)-1].Total += n.Note.Total()
+	}
+	if len(periods) > 0 {
+		periods[len(periods)-1].Projects = newProjectsData(totals)
+	}
+	return periods
+}
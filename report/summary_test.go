@@ -0,0 +1,78 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+func TestGroupByGolden(t *testing.T) {
+	reports := []struct {
+		Name   string
+		Report func(commitNoteDetails, OutputOptions) (string, error)
+	}{
+		{"summary", commitSummary},
+		{"project", projectSummary},
+	}
+
+	for _, r := range reports {
+		for _, output := range []string{OutputJSON, OutputCSV} {
+			got, err := r.Report(testNotes(), OutputOptions{Output: output, GroupBy: util.PeriodMonth})
+			if err != nil {
+				t.Errorf("%s -group-by month -output %s, want error nil got %s", r.Name, output, err)
+				continue
+			}
+			checkGolden(t, r.Name+"-month."+output, got)
+		}
+	}
+
+	// text output is not compared to a golden file because of terminal colors
+	got, err := projectSummary(testNotes(), OutputOptions{GroupBy: util.PeriodMonth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"June 2015", "1h 55m  0s gtm", "30m  0s web", "Total"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("project -group-by month, want %s got:\n%s", want, got)
+		}
+	}
+
+	got, err = commitSummary(testNotes(), OutputOptions{GroupBy: util.PeriodMonth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "June 2015") || !strings.Contains(got, "Total") {
+		t.Errorf("summary -group-by month, want June 2015 and Total got:\n%s", got)
+	}
+}
+
+func TestProjectPeriods(t *testing.T) {
+	// June 28 2015 is a Sunday and June 30 2015 is a Tuesday
+	cases := []struct {
+		WeekStart time.Weekday
+		Labels    []string
+		Totals    []int
+	}{
+		{time.Sunday, []string{"Week of Jun 28 2015"}, []int{8700}},
+		{time.Monday, []string{"Week of Jun 29 2015", "Week of Jun 22 2015"}, []int{6900, 1800}},
+	}
+
+	for _, tc := range cases {
+		periods := testNotes().projectPeriods(util.PeriodWeek, tc.WeekStart)
+		if len(periods) != len(tc.Labels) {
+			t.Errorf("projectPeriods(week) week start %s, want %d periods got %+v", tc.WeekStart, len(tc.Labels), periods)
+			continue
+		}
+		for i, p := range periods {
+			if p.Label != tc.Labels[i] || p.Total != tc.Totals[i] {
+				t.Errorf("projectPeriods(week) week start %s, want %s %d got %s %d", tc.WeekStart, tc.Labels[i], tc.Totals[i], p.Label, p.Total)
+			}
+		}
+	}
+}
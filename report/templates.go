@@ -20,30 +20,53 @@ const (
 	{{- if $line.CommitLine }}
 		{{- FormatDuration $line.Total | printf "\n%14s" }} {{ printf $greenFormat $line.Subject }} [{{ $line.Project }}]
 	{{- end }}
+	{{- if $line.GrandTotal }}
+		{{- printf "\n" }}
+		{{- printf $boldFormat "Total" }}
+		{{- FormatDuration $line.Total | printf "\n%14s" | printf $boldFormat }}
+		{{- printf "\n" }}
+	{{- end }}
 {{- end -}}`
 	projectTotalsTpl string = `
 {{- $boldFormat := .BoldFormat }}
 {{- range $project, $total := .Projects }}
 	{{- FormatDuration $total | printf "\n%14s" }} {{ printf $boldFormat $project }}
 {{- end -}}`
+	projectPeriodsTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{- range .Periods }}
+{{ printf $boldFormat .Label }}
+	{{- range .Projects }}
+		{{- FormatDuration .Seconds | printf "\n%14s" }} {{ .Project }}
+	{{- end }}
+{{ FormatDuration .Total | printf "%14s" }}
+{{ end }}
+{{ printf $boldFormat "Total" }}
+{{- range .Projects }}
+	{{- FormatDuration .Seconds | printf "\n%14s" }} {{ printf $boldFormat .Project }}
+{{- end }}
+{{ FormatDuration .Total | printf "%14s" | printf $boldFormat }}
+`
 	commitsTpl string = `
 {{ $boldFormat := .BoldFormat }}
 {{ $greenFormat := .GreenFormat }}
 {{- $fullMessage := .FullMessage }}
 {{- range $note := .Notes }}
 	{{- $total := .Note.Total }}
-	{{- printf $boldFormat $note.Hash }} {{ printf $greenFormat $note.Subject }}{{- printf "\n" }}
+	{{- printf $boldFormat $note.Hash }} {{ printf $greenFormat $note.Subject }}{{ if $note.Signature }} [{{ $note.Signature }}]{{ end }}{{- printf "\n" }}
 	{{- $note.Date }} {{ printf $boldFormat $note.Project }} {{ $note.Author }}{{- printf "\n" }}
 	{{- if $fullMessage}}{{- if $note.Message }}{{- printf "\n"}}{{- $note.Message }}{{- printf "\n"}}{{end}}{{end}}
 	{{- range $i, $f := .Note.Files }}
 		{{- if $f.IsApp }}
 			{{- FormatDuration $f.TimeSpent | printf "\n%14s" }} {{ Percent $f.TimeSpent $total | printf "%3.0f"}}% [{{ $f.Status }}] [app] {{$f.GetAppName }}
+		{{- else if $f.IsHidden }}
+			{{- FormatDuration $f.TimeSpent | printf "\n%14s" }} {{ Percent $f.TimeSpent $total | printf "%3.0f"}}%     [files not shared]
 		{{- else }}
 			{{- FormatDuration $f.TimeSpent | printf "\n%14s" }} {{ Percent $f.TimeSpent $total | printf "%3.0f"}}% [{{ $f.Status }}] {{$f.ShortenSourceFile 100}}
 		{{- end }}
 	{{- end }}
 	{{- if len .Note.Files }}
-	{{- FormatDuration $total | printf "\n%14s" }}          {{ printf $boldFormat $note.Project }} [{{$note.LineAdd}} {{$note.LineDel}} = {{$note.LineDiff}}] [{{$note.ChangeRate}}/hr]{{ printf "\n\n" }}
+	{{- FormatDuration $total | printf "\n%14s" }}          {{ printf $boldFormat $note.Project }}{{ if not $note.Pending }} [{{$note.LineAdd}} {{$note.LineDel}} = {{$note.LineDiff}}] [{{$note.ChangeRate}}/hr]{{ end }}{{ printf "\n\n" }}
 	{{- else }}
 		{{- printf "\n" }}
 	{{- end }}
@@ -93,12 +116,169 @@ const (
 	{{- printf "%92d" .Timeline.Total | printf $boldFormat }}
 {{ end }}`
 
+	authorsTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{- $total := .Authors.Total }}
+{{ range $a := .Authors }}
+	{{- printf $boldFormat $a.Name }}{{ if $a.Email }} <{{ $a.Email }}>{{ end }}
+{{ $a.Duration | printf "%14s" }} {{ Percent $a.Seconds $total | printf "%3.0f" }}%  {{ $a.Commits }} commit{{ if ne $a.Commits 1 }}s{{ end }}, {{ FormatDuration $a.Average }} per commit
+	{{- range $f := $a.Files }}
+		{{- if $f.IsApp }}
+{{ $f.Duration | printf "%14s" }} {{ Percent $f.Seconds $a.Seconds | printf "%3.0f" }}%  [app] {{ $f.GetAppName }} in {{ $f.Project }}
+		{{- else if $f.IsHidden }}
+{{ $f.Duration | printf "%14s" }} {{ Percent $f.Seconds $a.Seconds | printf "%3.0f" }}%  [files not shared] in {{ $f.Project }}
+		{{- else }}
+{{ $f.Duration | printf "%14s" }} {{ Percent $f.Seconds $a.Seconds | printf "%3.0f" }}%  {{ $f.Project }}/{{ $f.Filename }}
+		{{- end }}
+	{{- end }}
+
+{{ end }}
+{{- if len .Authors }}
+	{{- FormatDuration $total | printf "%14s" }}
+{{ end }}`
+
+	dirsTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{- $total := .Dirs.Total }}
+{{ range $d := .Dirs }}
+	{{- if $d.StartProject }}
+		{{- printf $boldFormat $d.Project }}
+{{ end }}
+	{{- $d.Duration | printf "%14s" }} {{ Percent $d.Seconds $total | printf "%3.0f" }}% {{ FormatDuration $d.Average | printf "%14s" }}/commit  {{ $d.Indent }}{{ $d.Name }}
+{{ end }}
+{{- if len .Dirs }}
+	{{- FormatDuration $total | printf "%14s" }}
+{{ end }}`
+
+	categoriesTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{- with .Categories.Categories }}
+{{- $total := .Total }}
+{{ printf $boldFormat "Categories" }}
+{{ range . }}
+	{{- FormatDuration .Seconds | printf "%14s" }} {{ Percent .Seconds $total | printf "%3.0f" }}% {{ printf "%5d" .Commits }} commits  {{ .Name }}
+{{ end }}
+{{- FormatDuration $total | printf "%14s" }}
+{{ end }}
+{{- with .Categories.Languages }}
+{{- $total := .Total }}
+{{ printf $boldFormat "Languages" }}
+{{ range . }}
+	{{- FormatDuration .Seconds | printf "%14s" }} {{ Percent .Seconds $total | printf "%3.0f" }}% {{ printf "%5d" .Commits }} commits  {{ .Name }}
+{{ end }}
+{{- FormatDuration $total | printf "%14s" }}
+{{ end }}`
+
+	issuesTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{- $total := .Issues.Total }}
+{{- with .Issues.Issues }}
+{{ printf $boldFormat "Issues" }}
+{{ range . }}
+	{{- FormatDuration .Seconds | printf "%14s" }} {{ Percent .Seconds $total | printf "%3.0f" }}% {{ len .Commits | printf "%5d" }} commits  {{ .Key }}  {{ .ProjectList }}
+{{ end }}
+{{- FormatDuration .Total | printf "%14s" }}
+{{ end }}
+{{- with .Issues.Unkeyed.Commits }}
+{{ printf $boldFormat "Without an issue key" }}
+{{ range . }}
+	{{- FormatDuration .Seconds | printf "%14s" }}  {{ .Hash }} {{ .Subject }} [{{ .Project }}]
+{{ end }}
+{{- FormatDuration $.Issues.Unkeyed.Seconds | printf "%14s" }}
+{{ end }}`
+
+	sessionsTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{- range .Days }}
+{{ printf $boldFormat (.Date.Format "Mon Jan 02 2006") }}
+
+{{ range .Sessions }}
+{{- FormatDuration .Seconds | printf "%14s" }}  {{ .Start.Format "15:04" }} - {{ .End.Format "15:04" }}  {{ .ProjectList }}
+{{ range .Files }}
+	{{- FormatDuration .Seconds | printf "%14s" }}                 {{ .Project }}/{{ .SourceFile }}
+{{ end }}
+{{- end }}
+{{- FormatDuration .Sessions.Total | printf "%14s" }}
+{{ with .Gaps }}
+{{ range . }}
+	{{- FormatDuration .Seconds | printf "%14s" }}  {{ .Start.Format "15:04" }} - {{ .End.Format "15:04" }}  idle
+{{ end }}
+{{- end }}
+{{- end }}`
+
+	focusTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{- with .Days }}
+{{ printf "%-15s %14s %14s %6s %16s %16s %5s" "" "Time" "Deep Work" "Blocks" "Project Switches" "File Switches/hr" "Score" | printf $boldFormat }}
+{{ range . }}
+	{{- .Date.Format "Mon Jan 02 2006" }} {{ FormatDuration .Seconds | printf "%14s" }} {{ FormatDuration .DeepWork.Total | printf "%14s" }} {{ len .DeepWork | printf "%6d" }} {{ printf "%16d" .ProjectSwitches }} {{ printf "%16.1f" .FileSwitchesPerHour }} {{ printf "%5d" .Score }}
+{{ end }}
+{{- with $.Days.Total }}
+	{{- printf "%-15s" "" }} {{ FormatDuration .Seconds | printf "%14s" }} {{ FormatDuration .DeepWork.Total | printf "%14s" }} {{ len .DeepWork | printf "%6d" }} {{ printf "%16d" .ProjectSwitches }} {{ printf "%16.1f" .FileSwitchesPerHour }} {{ printf "%5d" $.Days.Score }}
+{{ end }}
+{{- end }}
+{{- with .Days.Total.DeepWork }}
+{{ printf $boldFormat "Deep Work" }}
+{{ range . }}
+	{{- FormatDuration .Seconds | printf "%14s" }}  {{ .Start.Format "Mon Jan 02 15:04" }} - {{ .End.Format "15:04" }}  {{ .ProjectList }}
+{{ end }}
+{{- end }}`
+
+	compareTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{ printf "%s - %s compared to %s - %s" (.Current.Start.Format "2006-01-02") (.Current.End.Format "2006-01-02") (.Previous.Start.Format "2006-01-02") (.Previous.End.Format "2006-01-02") | printf $boldFormat }}
+{{ printf "%14s %14s %15s" "Current" "Previous" "Change" }}
+{{- range $section := .Comparison.Sections }}
+{{- if $section.Entries }}
+
+{{ printf $boldFormat $section.Name }}
+{{- range $section.Entries }}
+{{ FormatDuration .Current | printf "%14s" }} {{ FormatDuration .Previous | printf "%14s" }} {{ printf "%15s" .FormatDelta }} {{ printf "%6s" .FormatPercent }}  {{ .Name }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{ with .Comparison.Total }}
+	{{- FormatDuration .Current | printf "%14s" | printf $boldFormat }} {{ FormatDuration .Previous | printf "%14s" | printf $boldFormat }} {{ printf "%15s" .FormatDelta | printf $boldFormat }} {{ printf "%6s" .FormatPercent | printf $boldFormat }}  {{ printf $boldFormat "Total" }}
+{{ end }}`
+
+	timesheetTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{- $width := .Width }}
+{{- $decimal := .Decimal }}
+{{ printf "%-*s" $width "" }}{{ range .Sheet.Days }} {{ .Format "Mon" | printf "%6s" }}{{ end }}
+{{ printf "%-*s" $width "" }}{{ range .Sheet.Days }} {{ .Format "01-02" | printf "%6s" }}{{ end }} {{ printf "%8s" "Total" }}
+{{ range .Sheet.Rows }}
+	{{- printf "%-*s" $width .Name | printf $boldFormat }}{{ range .Days }} {{ FormatHours . $decimal | printf "%6s" }}{{ end }} {{ FormatHours .Total $decimal | printf "%8s" }}
+{{ end }}
+{{- printf "%-*s" $width "Total" | printf $boldFormat }}{{ range .Sheet.Totals }} {{ FormatHours . $decimal | printf "%6s" }}{{ end }} {{ FormatHours .Sheet.Total $decimal | printf "%8s" | printf $boldFormat }}
+`
+
+	invoiceTpl string = `
+{{- $boldFormat := .BoldFormat }}
+{{ if or .From .To }}{{ printf "Invoice %s - %s" .From .To | printf $boldFormat }}{{ else }}{{ printf "Invoice" | printf $boldFormat }}{{ end }}
+{{ range .Invoice.Projects }}
+{{ printf $boldFormat .Project }}
+{{ printf "%8s %16s %16s  %s" "Hours" "Rate" "Amount" "Description" }}
+{{ range .Items }}
+	{{- FormatHours .Seconds true | printf "%8s" }} {{ printf "%s %s" (FormatMoney .Rate) .Currency | printf "%16s" }} {{ printf "%s %s" (FormatMoney .Amount) .Currency | printf "%16s" }}  {{ .Subject }}
+{{ end }}
+{{- range .Subtotals }}
+	{{- FormatHours .Seconds true | printf "%8s" }} {{ printf "%16s" "" }} {{ printf "%s %s" (FormatMoney .Amount) .Currency | printf "%16s" }}  Subtotal
+{{ end }}
+{{- end }}
+{{ range .Invoice.Totals }}
+	{{- FormatHours .Seconds true | printf "%8s" }} {{ printf "%16s" "" }} {{ printf "%s %s" (FormatMoney .Amount) .Currency | printf "%16s" | printf $boldFormat }}  Total
+{{ end }}`
+
 	// TODO: determine left padding based on total hours
 	filesTpl string = `
 {{- $total := .Files.Total }}
 {{ range $i, $f := .Files }}
 	{{- if $f.IsApp }}
 		{{- $f.Duration | printf "%14s" }} {{ Percent $f.Seconds $total | printf "%3.0f"}}%  [app] {{ $f.GetAppName }}
+	{{- else if $f.IsHidden }}
+		{{- $f.Duration | printf "%14s" }} {{ Percent $f.Seconds $total | printf "%3.0f"}}%  [files not shared]
 	{{- else }}
 		{{- $f.Duration | printf "%14s" }} {{ Percent $f.Seconds $total | printf "%3.0f"}}%  {{ $f.Filename }}
 	{{- end }}
@@ -106,4 +286,170 @@ const (
 {{- if len .Files }}
 	{{- .Files.Duration | printf "%14s" }}
 {{ end }}`
+
+	htmlTpl string = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>gtm report {{ .From }} - {{ .To }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292e; margin: 2em auto; max-width: 1100px; padding: 0 1em; }
+h1 { font-size: 1.6em; margin-bottom: 0; }
+h2 { font-size: 1.2em; border-bottom: 1px solid #e1e4e8; padding-bottom: .3em; margin-top: 2em; }
+.meta { color: #586069; margin-top: .3em; }
+.total { font-size: 1.4em; font-weight: bold; }
+table { border-collapse: collapse; width: 100%; font-size: .9em; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #eaecef; vertical-align: top; }
+td.num, th.num { text-align: right; white-space: nowrap; }
+th.sort { cursor: pointer; user-select: none; }
+th.sort:after { content: " \2195"; color: #959da5; }
+.bar { background: #4e79a7; height: 10px; }
+.swatch { display: inline-block; width: 10px; height: 10px; margin-right: 6px; }
+.muted { color: #959da5; }
+svg text { font-size: 10px; fill: #586069; }
+code { font-size: .95em; }
+</style>
+</head>
+<body>
+<h1>Time Report</h1>
+<p class="meta">{{ if .From }}{{ .From }} to {{ .To }} &middot; {{ end }}{{ len .Commits }} commits &middot; generated {{ .Generated }}</p>
+<p class="total">{{ FormatDuration .Total }}</p>
+
+<h2>Time by Day</h2>
+<svg width="{{ .ChartWidth }}" height="{{ .ChartHeight }}" role="img">
+{{- range .Chart }}
+<rect x="{{ .X }}" y="{{ .Y }}" width="{{ .Width }}" height="{{ .Height }}" fill="{{ .Color }}"><title>{{ .Title }}</title></rect>
+{{- end }}
+{{- range .ChartLabels }}
+<text x="{{ .X }}" y="{{ .Y }}" text-anchor="middle">{{ .Text }}</text>
+{{- end }}
+</svg>
+<p>
+{{- range .Projects }}
+<span class="swatch" style="background: {{ .Color }}"></span>{{ .Name }}&nbsp;&nbsp;
+{{- end }}
+</p>
+
+<h2>Time by Hour of Day</h2>
+<svg width="580" height="180" role="img">
+{{- range .HeatLabels }}
+<text x="{{ .X }}" y="{{ .Y }}">{{ .Text }}</text>
+{{- end }}
+{{- range .Heatmap }}
+<rect x="{{ .X }}" y="{{ .Y }}" width="{{ .Width }}" height="{{ .Height }}" fill="{{ .Color }}" fill-opacity="{{ printf "%.2f" .Opacity }}"><title>{{ .Title }}</title></rect>
+{{- end }}
+</svg>
+
+{{- $total := .Total }}
+
+<h2>Projects</h2>
+<table class="sortable">
+<thead><tr><th class="sort">Project</th><th class="sort num">Time</th><th class="sort num">%</th><th></th></tr></thead>
+<tbody>
+{{- range .Projects }}
+<tr><td><span class="swatch" style="background: {{ .Color }}"></span>{{ .Name }}</td><td class="num" data-value="{{ .Seconds }}">{{ FormatDuration .Seconds }}</td><td class="num" data-value="{{ .Seconds }}">{{ Percent .Seconds $total | printf "%.0f" }}%</td><td style="width: 40%"><div class="bar" style="width: {{ Percent .Seconds $total | printf "%.1f" }}%"></div></td></tr>
+{{- end }}
+</tbody>
+</table>
+
+<h2>Files</h2>
+<table class="sortable">
+<thead><tr><th class="sort">Project</th><th class="sort">File</th><th class="sort num">Time</th><th class="sort num">%</th></tr></thead>
+<tbody>
+{{- range .Files }}
+<tr><td>{{ .Project }}</td><td>{{ if eq .File.Type "app" }}<span class="muted">[app]</span> {{ .File.File }}{{ else if eq .File.Type "hidden" }}<span class="muted">[files not shared]</span>{{ else }}<code>{{ .File.File }}</code>{{ end }}</td><td class="num" data-value="{{ .File.Seconds }}">{{ FormatDuration .File.Seconds }}</td><td class="num" data-value="{{ .File.Seconds }}">{{ Percent .File.Seconds $total | printf "%.1f" }}%</td></tr>
+{{- end }}
+</tbody>
+</table>
+
+<h2>Commits</h2>
+<table class="sortable">
+<thead><tr><th class="sort">Date</th><th class="sort">Project</th><th class="sort">Commit</th><th class="sort">Subject</th><th class="sort">Author</th><th class="sort num">Lines</th><th class="sort num">Time</th></tr></thead>
+<tbody>
+{{- range .Commits }}
+<tr><td data-value="{{ Unix .Date }}">{{ .Date.Format "2006-01-02 15:04" }}</td><td>{{ .Project }}</td><td><code title="{{ .Hash }}">{{ printf "%.7s" .Hash }}</code></td><td>{{ .Subject }}</td><td>{{ .Author }}</td><td class="num" data-value="{{ .LinesAdded }}">+{{ .LinesAdded }} -{{ .LinesDeleted }}</td><td class="num" data-value="{{ .Seconds }}">{{ FormatDuration .Seconds }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+
+<script>
+(function() {
+  function value(row, idx) {
+    var cell = row.cells[idx];
+    var v = cell.getAttribute("data-value");
+    return v === null ? cell.textContent.toLowerCase() : parseFloat(v);
+  }
+  var tables = document.querySelectorAll("table.sortable");
+  for (var t = 0; t < tables.length; t++) {
+    (function(table) {
+      var headers = table.querySelectorAll("th.sort");
+      for (var h = 0; h < headers.length; h++) {
+        (function(th) {
+          th.addEventListener("click", function() {
+            var idx = th.cellIndex;
+            var asc = th.getAttribute("data-asc") !== "true";
+            th.setAttribute("data-asc", asc);
+            var body = table.tBodies[0];
+            var rows = Array.prototype.slice.call(body.rows);
+            rows.sort(function(a, b) {
+              var x = value(a, idx), y = value(b, idx);
+              return (x < y ? -1 : x > y ? 1 : 0) * (asc ? 1 : -1);
+            });
+            for (var r = 0; r < rows.length; r++) {
+              body.appendChild(rows[r]);
+            }
+          });
+        })(headers[h]);
+      }
+    })(tables[t]);
+  }
+})();
+</script>
+</body>
+</html>
+`
+
+	commitsMarkdownTpl string = `| Commit | Subject | Project | Author | Time |
+| --- | --- | --- | --- | ---: |
+{{- range .Notes }}
+| {{ Code .Hash }} | {{ Cell .Subject }} | {{ Cell .Project }} | {{ Cell .Author }} | {{ Duration .Note.Total }} |
+{{- end }}
+| | **Total** | | | **{{ Duration .Notes.Total }}** |
+{{- range $note := .Notes }}
+	{{- if .Note.Files }}
+
+<details>
+<summary><code>{{ .Hash }}</code> {{ html .Subject }} ({{ Duration .Note.Total }})</summary>
+
+| File | Status | Time | % |
+| --- | :---: | ---: | ---: |
+		{{- $total := .Note.Total }}
+		{{- range $f := .Note.Files }}
+| {{ if $f.IsApp }}{{ $f.GetAppName }} (app){{ else if $f.IsHidden }}*files not shared*{{ else }}{{ Code $f.SourceFile }}{{ end }} | {{ $f.Status }} | {{ Duration $f.TimeSpent }} | {{ Percent $f.TimeSpent $total | printf "%.0f" }}% |
+		{{- end }}
+
+</details>
+	{{- end }}
+{{- end }}
+`
+
+	filesMarkdownTpl string = `| File | Time | % |
+| --- | ---: | ---: |
+{{- $total := .Files.Total }}
+{{- range $f := .Files }}
+| {{ if $f.IsApp }}{{ $f.GetAppName }} (app){{ else if $f.IsHidden }}*files not shared*{{ else }}{{ Code $f.Filename }}{{ end }} | {{ Duration $f.Seconds }} | {{ Percent $f.Seconds $total | printf "%.0f" }}% |
+{{- end }}
+| **Total** | **{{ Duration $total }}** | |
+`
+
+	summaryMarkdownTpl string = `| Date | Subject | Project | Time |
+| --- | --- | --- | ---: |
+{{- range $day := .Days }}
+	{{- range $i, $c := $day.Commits }}
+| {{ if eq $i 0 }}{{ if $day.Period }}{{ $day.Period }}{{ else }}{{ $day.Date }}{{ end }}{{ end }} | {{ Cell $c.Subject }} | {{ Cell $c.Project }} | {{ Duration $c.Seconds }} |
+	{{- end }}
+| | *{{ if $day.Period }}{{ $day.Period }}{{ else }}{{ $day.Date }}{{ end }}* | | *{{ Duration $day.Seconds }}* |
+{{- end }}
+| | **Total** | | **{{ Duration .Total }}** |
+`
 )
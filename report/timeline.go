@@ -16,6 +16,7 @@ type timelineCommitEntries []timelineCommitEntry
 
 type timelineCommitEntry struct {
 	Day     string
+	Date    string
 	Total   int
 	Commits [24]int
 }
@@ -58,7 +59,7 @@ func (c commitNoteDetails) timelineCommits() (timelineCommitEntries, error) {
 		if entry, ok := timelineMap[day]; !ok {
 			var commits [24]int
 			commits[hour] = 1
-			timelineMap[day] = timelineCommitEntry{Day: t.Format("Mon Jan 02"), Commits: commits, Total: 1}
+			timelineMap[day] = timelineCommitEntry{Day: t.Format("Mon Jan 02"), Date: day, Commits: commits, Total: 1}
 		} else {
 			entry.inc(hour)
 			timelineMap[day] = entry
@@ -83,7 +84,8 @@ func (c commitNoteDetails) timeline() (timelineEntries, error) {
 	for _, n := range c {
 		for _, f := range n.Note.Files {
 			for epoch, secs := range f.Timeline {
-				t := time.Unix(epoch, 0)
+				// timelines are bucketed in the reporting time zone of the commit
+				t := time.Unix(epoch, 0).In(n.When.Location())
 				day := t.Format("2006-01-02")
 				hour, err := strconv.Atoi(t.Format("15"))
 				if err != nil {
@@ -92,7 +94,7 @@ func (c commitNoteDetails) timeline() (timelineEntries, error) {
 				if entry, ok := timelineMap[day]; !ok {
 					var hours [24]int
 					hours[hour] = secs
-					timelineMap[day] = timelineEntry{Day: t.Format("Mon Jan 02"), Hours: hours, Seconds: secs}
+					timelineMap[day] = timelineEntry{Day: t.Format("Mon Jan 02"), Date: day, Hours: hours, Seconds: secs}
 				} else {
 					entry.add(secs, hour)
 					timelineMap[day] = entry
@@ -140,6 +142,7 @@ func (t timelineEntries) HourMaxSeconds() int {
 
 type timelineEntry struct {
 	Day     string
+	Date    string
 	Seconds int
 	Hours   [24]int
 }
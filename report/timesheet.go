@@ -0,0 +1,158 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+// untagged is the timesheet row for projects without tags
+const untagged = "(untagged)"
+
+type timesheet struct {
+	Days   []time.Time
+	Rows   []timesheetRow
+	Totals []int
+	Total  int
+}
+
+type timesheetRow struct {
+	Name  string
+	Days  []int
+	Total int
+}
+
+// NameWidth returns the width of the widest row name
+func (t timesheet) NameWidth() int {
+	w := len("Total")
+	for _, r := range t.Rows {
+		if len(r.Name) > w {
+			w = len(r.Name)
+		}
+	}
+	return w
+}
+
+// timesheet returns the time spent by day for each project, or for each tag if byTag,
+// time is placed on the day it was spent and days outside of dateRange are excluded.
+// Each day is rounded to the nearest roundMins minutes, 0 is no rounding.
+func (c commitNoteDetails) timesheet(byTag bool, dateRange util.DateRange, roundMins int) timesheet {
+	const dayFormat = "2006-01-02"
+
+	rowsMap := map[string]map[string]int{}
+	days := map[string]bool{}
+	for _, n := range c {
+		names := []string{n.Project}
+		if byTag {
+			names = n.Tags
+			if len(names) == 0 {
+				names = []string{untagged}
+			}
+		}
+		for _, f := range n.Note.Files {
+			for epoch, secs := range f.Timeline {
+				t := time.Unix(epoch, 0).In(n.When.Location())
+				if dateRange.IsSet() && !dateRange.Within(t) {
+					continue
+				}
+				day := t.Format(dayFormat)
+				days[day] = true
+				for _, name := range names {
+					if _, ok := rowsMap[name]; !ok {
+						rowsMap[name] = map[string]int{}
+					}
+					rowsMap[name][day] += secs
+				}
+			}
+		}
+	}
+
+	// the columns are every day in the date range or the days with time spent
+	var first, last time.Time
+	if !dateRange.Start.IsZero() && !dateRange.End.IsZero() {
+		first = dayStart(dateRange.Start)
+		last = dayStart(dateRange.End)
+	} else {
+		keys := make([]string, 0, len(days))
+		for day := range days {
+			keys = append(keys, day)
+		}
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			first, _ = time.Parse(dayFormat, keys[0])
+			last, _ = time.Parse(dayFormat, keys[len(keys)-1])
+		}
+	}
+
+	sheet := timesheet{Days: []time.Time{}, Rows: []timesheetRow{}, Totals: []int{}}
+	if first.IsZero() {
+		return sheet
+	}
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		sheet.Days = append(sheet.Days, d)
+	}
+	sheet.Totals = make([]int, len(sheet.Days))
+
+	names := make([]string, 0, len(rowsMap))
+	for name := range rowsMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		row := timesheetRow{Name: name, Days: make([]int, len(sheet.Days))}
+		for i, d := range sheet.Days {
+			secs := roundSeconds(rowsMap[name][d.Format(dayFormat)], roundMins*60)
+			row.Days[i] = secs
+			row.Total += secs
+			sheet.Totals[i] += secs
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+
+	// projects with several tags are counted once in the totals
+	if byTag {
+		sheet.Totals = make([]int, len(sheet.Days))
+		projects := c.timesheet(false, dateRange, roundMins)
+		for _, r := range projects.Rows {
+			for i, secs := range r.Days {
+				sheet.Totals[i] += secs
+			}
+		}
+	}
+	for _, secs := range sheet.Totals {
+		sheet.Total += secs
+	}
+
+	return sheet
+}
+
+// dayStart returns midnight of t's day as a date without a time zone
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// roundSeconds rounds seconds to the nearest unit, 0 is no rounding
+func roundSeconds(secs, unit int) int {
+	if unit <= 0 {
+		return secs
+	}
+	return (secs + unit/2) / unit * unit
+}
+
+// formatHours returns seconds as hours and minutes, i.e. 7:15, or as decimal hours, i.e. 7.25
+func formatHours(secs int, decimal bool) string {
+	if secs == 0 {
+		return ""
+	}
+	if decimal {
+		return fmt.Sprintf("%.2f", float64(secs)/3600)
+	}
+	return hoursMinutes(secs)
+}
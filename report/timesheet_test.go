@@ -0,0 +1,118 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/git-time-metric/gtm/note"
+	"github.com/git-time-metric/gtm/util"
+)
+
+func timesheetNotes() commitNoteDetails {
+	zone := time.FixedZone("-0500", -5*3600)
+	day := func(d, h int) int64 { return time.Date(2015, 6, d, h, 0, 0, 0, zone).Unix() }
+	return commitNoteDetails{
+		{
+			Project: "gtm",
+			Tags:    []string{"go", "work"},
+			When:    time.Date(2015, 7, 1, 9, 0, 0, 0, zone),
+			Note: note.CommitNote{Files: []note.FileDetail{
+				// 11pm local time on the 29th is the 30th in UTC
+				{SourceFile: "main.go", TimeSpent: 3000, Timeline: map[int64]int{day(29, 23): 1200, day(30, 9): 1800}},
+			}},
+		},
+		{
+			Project: "web",
+			When:    time.Date(2015, 6, 30, 17, 0, 0, 0, zone),
+			Note: note.CommitNote{Files: []note.FileDetail{
+				{SourceFile: "index.html", TimeSpent: 700, Timeline: map[int64]int{day(30, 16): 700}},
+			}},
+		},
+	}
+}
+
+func TestTimesheet(t *testing.T) {
+	sheet := timesheetNotes().timesheet(false, util.DateRange{}, 0)
+
+	wantDays := []time.Time{
+		time.Date(2015, 6, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(sheet.Days, wantDays) {
+		t.Errorf("timesheet(), want days %v got %v", wantDays, sheet.Days)
+	}
+	wantRows := []timesheetRow{
+		{Name: "gtm", Days: []int{1200, 1800}, Total: 3000},
+		{Name: "web", Days: []int{0, 700}, Total: 700},
+	}
+	if !reflect.DeepEqual(sheet.Rows, wantRows) {
+		t.Errorf("timesheet(), want rows %+v got %+v", wantRows, sheet.Rows)
+	}
+	if !reflect.DeepEqual(sheet.Totals, []int{1200, 2500}) || sheet.Total != 3700 {
+		t.Errorf("timesheet(), want totals [1200 2500] 3700 got %v %d", sheet.Totals, sheet.Total)
+	}
+
+	// rounded to the nearest 15 minutes by day
+	sheet = timesheetNotes().timesheet(false, util.DateRange{}, 15)
+	if !reflect.DeepEqual(sheet.Rows[1].Days, []int{0, 900}) || sheet.Total != 3600 {
+		t.Errorf("timesheet() rounded, want web [0 900] and total 3600 got %v %d", sheet.Rows[1].Days, sheet.Total)
+	}
+
+	// projects with several tags are counted once in the totals
+	sheet = timesheetNotes().timesheet(true, util.DateRange{}, 0)
+	names := []string{}
+	for _, r := range sheet.Rows {
+		names = append(names, r.Name)
+	}
+	if !reflect.DeepEqual(names, []string{untagged, "go", "work"}) || sheet.Total != 3700 {
+		t.Errorf("timesheet() by tag, want rows [(untagged) go work] total 3700 got %v %d", names, sheet.Total)
+	}
+
+	// every day of the date range is a column and time outside of the range is excluded
+	zone := time.FixedZone("-0500", -5*3600)
+	dr := util.DateRange{
+		Start: time.Date(2015, 6, 30, 0, 0, 0, 0, zone),
+		End:   time.Date(2015, 7, 2, 0, 0, 0, 0, zone).Add(-time.Nanosecond)}
+	sheet = timesheetNotes().timesheet(false, dr, 0)
+	if len(sheet.Days) != 2 || sheet.Days[0].Day() != 30 || sheet.Days[1].Day() != 1 {
+		t.Errorf("timesheet() with date range, want days 06-30 and 07-01 got %v", sheet.Days)
+	}
+	if sheet.Total != 2500 {
+		t.Errorf("timesheet() with date range, want total 2500 got %d", sheet.Total)
+	}
+}
+
+func TestFormatHours(t *testing.T) {
+	cases := []struct {
+		Secs    int
+		Decimal bool
+		Want    string
+	}{
+		{0, false, ""},
+		{26100, false, "7:15"},
+		{26100, true, "7.25"},
+		{29, false, "0:00"},
+		{3570, false, "1:00"},
+	}
+	for _, tc := range cases {
+		if got := formatHours(tc.Secs, tc.Decimal); got != tc.Want {
+			t.Errorf("formatHours(%d, %t), want %s got %s", tc.Secs, tc.Decimal, tc.Want, got)
+		}
+	}
+}
+
+func TestTimesheetGolden(t *testing.T) {
+	for _, output := range []string{OutputJSON, OutputCSV, OutputTSV} {
+		got, err := timesheetReport(timesheetNotes(), OutputOptions{Output: output})
+		if err != nil {
+			t.Errorf("timesheet -output %s, want error nil got %s", output, err)
+			continue
+		}
+		checkGolden(t, "timesheet."+output, got)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/git-time-metric/gtm/util"
+)
+
+const (
+	// TemplateFormatPrefix selects a user-defined template with the format option, i.e. template:weekly
+	TemplateFormatPrefix = "template:"
+	// TemplateExt is the file extension of user-defined templates
+	TemplateExt = ".tmpl"
+)
+
+// TemplateData is the data model for user-defined templates, it is stable between releases.
+// Fields are the same as the json output of the reports, for example
+//
+//	{{ range .Projects }}{{ .Project }} {{ Hours .Seconds }}
+//	{{ end }}Total {{ Duration .Seconds }} from {{ .From }} to {{ .To }}
+type TemplateData struct {
+	// Generated is when the report was run
+	Generated time.Time
+	// From and To are the first and last days with commits, yyyy-mm-dd
+	From, To string
+	// Seconds is the total time spent
+	Seconds  int
+	Commits  []CommitData
+	Summary  []SummaryData
+	Projects []ProjectData
+	Files    []FileData
+	Timeline []TimelineData
+	Authors  []AuthorData
+	Dirs     []DirData
+}
+
+// TemplateFuncs are the functions available to user-defined templates in addition to
+// the text/template builtins, they are stable between releases.
+//
+//	Duration secs            1h 5m 3s
+//	Hours secs               decimal hours, i.e. 1.25
+//	HoursMinutes secs        hours and minutes, i.e. 1:15
+//	Percent val total        val as a percentage of total, i.e. {{ Percent .Seconds $.Seconds | printf "%.0f" }}
+//	Date layout time         time formatted with a Go layout, i.e. {{ Date "Jan 02" .Date }}
+//	LeftPad s len            s padded with spaces on the left to len
+//	RightPad s len           s padded with spaces on the right to len
+//	Upper s, Lower s         s in upper or lower case
+//	Join list sep            strings joined with sep
+//	Truncate s len           s shortened to len characters
+//	JSON value               value as json
+var TemplateFuncs = template.FuncMap{
+	"Duration":     func(secs int) string { return strings.Join(strings.Fields(util.FormatDuration(secs)), " ") },
+	"Hours":        func(secs int) string { return fmt.Sprintf("%.2f", float64(secs)/3600) },
+	"HoursMinutes": hoursMinutes,
+	"Percent":      util.Percent,
+	"Date":         func(layout string, t time.Time) string { return t.Format(layout) },
+	"LeftPad":      func(s string, n int) string { return util.LeftPad2Len(s, " ", n) },
+	"RightPad":     func(s string, n int) string { return util.RightPad2Len(s, " ", n) },
+	"Upper":        strings.ToUpper,
+	"Lower":        strings.ToLower,
+	"Join":         strings.Join,
+	"Truncate":     truncate,
+	"JSON":         templateJSON,
+}
+
+// hoursMinutes returns secs as hours and minutes rounded to the nearest minute, i.e. 1:05
+func hoursMinutes(secs int) string {
+	mins := (secs + 30) / 60
+	return fmt.Sprintf("%d:%02d", mins/60, mins%60)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n < 0 || len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+func templateJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// FindTemplate returns the path of a user-defined template, the first directory with
+// <name>.tmpl is used
+func FindTemplate(name string, dirs ...string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("Template name %q is not valid", name)
+	}
+	for _, d := range dirs {
+		p := filepath.Join(d, name+TemplateExt)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("Template %s not found in %s", name+TemplateExt, strings.Join(dirs, ", "))
+}
+
+// UserTemplate returns a report rendered with the user-defined template in tplPath
+func UserTemplate(projects []ProjectCommits, options OutputOptions, tplPath string) (string, error) {
+	tpl, err := ioutil.ReadFile(tplPath)
+	if err != nil {
+		return "", err
+	}
+	return userTemplate(options.limitNotes(retrieveNotes(projects, options, true, "")), options, filepath.Base(tplPath), string(tpl))
+}
+
+func userTemplate(notes commitNoteDetails, options OutputOptions, name, tpl string) (string, error) {
+	t, err := template.New(name).Funcs(TemplateFuncs).Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("Template %s is not valid, %s", name, err)
+	}
+
+	data, err := notes.templateData(options)
+	if err != nil {
+		return "", err
+	}
+
+	b := new(bytes.Buffer)
+	if err := t.Execute(b, data); err != nil {
+		return "", fmt.Errorf("Template %s failed, %s", name, err)
+	}
+	return b.String(), nil
+}
+
+func (c commitNoteDetails) templateData(options OutputOptions) (TemplateData, error) {
+	timeline, err := c.timeline()
+	if err != nil {
+		return TemplateData{}, err
+	}
+
+	projectTotals := map[string]int{}
+	for _, n := range c {
+		projectTotals[n.Project] += n.Note.Total()
+	}
+
+	data := TemplateData{
+		Generated: util.Now(),
+		Seconds:   c.Total(),
+		Commits:   c.commitsData(),
+		Summary:   c.summaryData("", options.WeekStart),
+		Projects:  newProjectsData(projectTotals),
+		Files:     c.files().filesData(),
+		Timeline:  timeline.timelineData(),
+		Authors:   c.authors().authorsData(),
+		Dirs:      c.dirs(options.Depth).dirsData(),
+	}
+	if len(c) > 0 {
+		// notes are sorted newest first
+		data.From = c[len(c)-1].When.Format("2006-01-02")
+		data.To = c[0].When.Format("2006-01-02")
+	}
+	return data, nil
+}
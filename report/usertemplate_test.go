@@ -0,0 +1,66 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUserTemplate(t *testing.T) {
+	tplPath, err := FindTemplate("weekly", filepath.Join("testdata", "missing"), filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl, err := ioutil.ReadFile(tplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := userTemplate(testNotes(), OutputOptions{}, "weekly.tmpl", string(tpl))
+	if err != nil {
+		t.Fatalf("userTemplate(weekly), want error nil got %s", err)
+	}
+	checkGolden(t, "weekly.txt", got)
+}
+
+func TestUserTemplateErrors(t *testing.T) {
+	cases := []struct {
+		Template string
+		Want     string
+	}{
+		{"{{ .Commits ", "Template bad.tmpl is not valid"},
+		{"{{ .NoSuchField }}", "Template bad.tmpl failed"},
+		{"{{ NoSuchFunc .Seconds }}", "Template bad.tmpl is not valid"},
+	}
+	for _, tc := range cases {
+		_, err := userTemplate(testNotes(), OutputOptions{}, "bad.tmpl", tc.Template)
+		if err == nil || !strings.Contains(err.Error(), tc.Want) {
+			t.Errorf("userTemplate(%s), want error %s got %v", tc.Template, tc.Want, err)
+		}
+	}
+}
+
+func TestHoursMinutes(t *testing.T) {
+	cases := map[int]string{0: "0:00", 29: "0:00", 30: "0:01", 3599: "1:00", 3689: "1:01", 5400: "1:30"}
+	for secs, want := range cases {
+		if got := hoursMinutes(secs); got != want {
+			t.Errorf("HoursMinutes(%d), want %s got %s", secs, want, got)
+		}
+	}
+}
+
+func TestFindTemplate(t *testing.T) {
+	for _, name := range []string{"", "../weekly", ".hidden", "a/b"} {
+		if _, err := FindTemplate(name, "testdata"); err == nil || !strings.Contains(err.Error(), "not valid") {
+			t.Errorf("FindTemplate(%s), want not valid error got %v", name, err)
+		}
+	}
+	if _, err := FindTemplate("missing", filepath.Join("testdata", "templates")); err == nil || !strings.Contains(err.Error(), "missing.tmpl not found") {
+		t.Errorf("FindTemplate(missing), want not found error got %v", err)
+	}
+}
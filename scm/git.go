@@ -76,13 +76,27 @@ type CommitLimiter struct {
 	HasAfter   bool
 	HasAuthor  bool
 	HasMessage bool
+	// Mailmap maps author aliases when matching the author, a repo's .mailmap is always used
+	Mailmap Mailmap
+	// TimeSpent selects commits that may contain time spent within the date range instead of
+	// commits authored within it, commits authored after the date range are included
+	TimeSpent bool
+	// Lookback includes commits authored this long before the date range when selecting by time spent,
+	// author dates are kept when amending and rebasing so time spent on long-running branches can be later
+	Lookback time.Duration
 }
 
 // NewCommitLimiter returns a new initialize CommitLimiter struct
+// Dates and predefined date ranges are determined in the time zone loc, nil defaults to the system's time zone
+// Weekly date ranges start on weekStart
 func NewCommitLimiter(
 	max int, fromDateStr, toDateStr, author, message string,
 	today, yesterday, thisWeek, lastWeek,
-	thisMonth, lastMonth, thisYear, lastYear bool) (CommitLimiter, error) {
+	thisMonth, lastMonth, thisYear, lastYear bool, weekStart time.Weekday, loc *time.Location) (CommitLimiter, error) {
+
+	if loc == nil {
+		loc = time.Local
+	}
 
 	const dateFormat = "2006-01-02"
 
@@ -118,35 +132,37 @@ func NewCommitLimiter(
 		toDate := time.Time{}
 
 		if fromDateStr != "" {
-			fromDate, err = time.Parse(dateFormat, fromDateStr)
+			fromDate, err = time.ParseInLocation(dateFormat, fromDateStr, loc)
 			if err != nil {
 				return CommitLimiter{}, err
 			}
 		}
 		if toDateStr != "" {
-			toDate, err = time.Parse(dateFormat, toDateStr)
+			toDate, err = time.ParseInLocation(dateFormat, toDateStr, loc)
 			if err != nil {
 				return CommitLimiter{}, err
 			}
+			// include commits thru the end of the day
+			toDate = toDate.AddDate(0, 0, 1).Add(-time.Nanosecond)
 		}
 		dateRange = util.DateRange{Start: fromDate, End: toDate}
 
 	case today:
-		dateRange = util.TodayRange()
+		dateRange = util.TodayRange(loc)
 	case yesterday:
-		dateRange = util.YesterdayRange()
+		dateRange = util.YesterdayRange(loc)
 	case thisWeek:
-		dateRange = util.ThisWeekRange()
+		dateRange = util.ThisWeekRange(weekStart, loc)
 	case lastWeek:
-		dateRange = util.LastWeekRange()
+		dateRange = util.LastWeekRange(weekStart, loc)
 	case thisMonth:
-		dateRange = util.ThisMonthRange()
+		dateRange = util.ThisMonthRange(loc)
 	case lastMonth:
-		dateRange = util.LastMonthRange()
+		dateRange = util.LastMonthRange(loc)
 	case thisYear:
-		dateRange = util.ThisYearRange()
+		dateRange = util.ThisYearRange(loc)
 	case lastYear:
-		dateRange = util.LastYearRange()
+		dateRange = util.LastYearRange(loc)
 	}
 
 	hasMax := max > 0
@@ -175,11 +191,11 @@ func (m CommitLimiter) filter(c *git.Commit, cnt int) (bool, bool, error) {
 		return false, true, nil
 	}
 
-	if m.DateRange.IsSet() && !m.DateRange.Within(c.Author().When) {
+	if m.DateRange.IsSet() && !m.within(c.Author().When) {
 		return false, false, nil
 	}
 
-	if m.HasAuthor && !strings.Contains(c.Author().Name, m.Author) {
+	if m.HasAuthor && !m.matchAuthor(c.Author().Name, c.Author().Email) {
 		return false, false, nil
 	}
 
@@ -190,6 +206,29 @@ func (m CommitLimiter) filter(c *git.Commit, cnt int) (bool, bool, error) {
 	return true, false, nil
 }
 
+// within returns true if a commit authored at t is selected by the date range
+func (m CommitLimiter) within(t time.Time) bool {
+	if !m.TimeSpent {
+		return m.DateRange.Within(t)
+	}
+	// time is committed after it is spent
+	if m.DateRange.Start.IsZero() {
+		return true
+	}
+	return !t.Before(m.DateRange.Start.Add(-m.Lookback))
+}
+
+// matchAuthor returns true if the author's name or email, or their aliases, contain the author substring
+func (m CommitLimiter) matchAuthor(name, email string) bool {
+	properName, properEmail := m.Mailmap.Lookup(name, email)
+	for _, s := range []string{name, email, properName, properEmail} {
+		if strings.Contains(s, m.Author) {
+			return true
+		}
+	}
+	return false
+}
+
 // CommitIDs returns commit SHA1 IDs starting from the head up to the limit
 func CommitIDs(limiter CommitLimiter, wd ...string) ([]string, error) {
 	var (
@@ -211,6 +250,14 @@ func CommitIDs(limiter CommitLimiter, wd ...string) ([]string, error) {
 	}
 	defer repo.Free()
 
+	if limiter.HasAuthor && repo.Workdir() != "" {
+		mm, err := LoadMailmap(filepath.Join(repo.Workdir(), MailmapFile))
+		if err != nil {
+			return commits, err
+		}
+		limiter.Mailmap = mm.Merge(limiter.Mailmap)
+	}
+
 	w, err = repo.Walk()
 	if err != nil {
 		return commits, err
@@ -251,6 +298,194 @@ func CommitIDs(limiter CommitLimiter, wd ...string) ([]string, error) {
 	return commits, nil
 }
 
+// RevParse returns the commit ids for revisions in any form git rev-parse accepts, i.e. branch
+// and tag names, HEAD~3, abbreviated ids and ranges such as main..feature or v1.0...v1.1.
+// Commit ids are returned in the order given and without duplicates, a range's commits are newest first.
+// ErrSHA256Unsupported is returned for SHA-256 repositories and object ids.
+func RevParse(revs []string, wd ...string) ([]string, error) {
+	var (
+		repo *git.Repository
+		err  error
+	)
+	commits := []string{}
+
+	if len(wd) > 0 {
+		repo, err = openRepository(wd[0])
+	} else {
+		repo, err = openRepository()
+	}
+	if err != nil {
+		return commits, err
+	}
+	defer repo.Free()
+
+	seen := map[string]bool{}
+	for _, rev := range revs {
+		if reSHA256ID.MatchString(rev) {
+			return commits, fmt.Errorf("Not a valid revision %s, %s", rev, ErrSHA256Unsupported)
+		}
+		ids, err := revParse(repo, rev)
+		if err != nil {
+			return commits, fmt.Errorf("Not a valid revision %s, %s", rev, err)
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				commits = append(commits, id)
+			}
+		}
+	}
+	return commits, nil
+}
+
+func revParse(repo *git.Repository, rev string) ([]string, error) {
+	spec, err := repo.Revparse(rev)
+	if err != nil {
+		return []string{}, err
+	}
+
+	if spec.Flags()&git.RevparseSingle != 0 {
+		id, err := peelCommitID(spec.From())
+		if err != nil {
+			return []string{}, err
+		}
+		return []string{id.String()}, nil
+	}
+
+	from, err := peelCommitID(spec.From())
+	if err != nil {
+		return []string{}, err
+	}
+	to, err := peelCommitID(spec.To())
+	if err != nil {
+		return []string{}, err
+	}
+
+	w, err := repo.Walk()
+	if err != nil {
+		return []string{}, err
+	}
+	defer w.Free()
+	w.Sorting(git.SortTopological | git.SortTime)
+
+	if err := w.Push(to); err != nil {
+		return []string{}, err
+	}
+	if spec.Flags()&git.RevparseMergeBase != 0 {
+		// a...b is the commits reachable from either but not from both
+		base, err := repo.MergeBase(from, to)
+		if err != nil {
+			return []string{}, err
+		}
+		if err := w.Push(from); err != nil {
+			return []string{}, err
+		}
+		if err := w.Hide(base); err != nil {
+			return []string{}, err
+		}
+	} else if err := w.Hide(from); err != nil {
+		return []string{}, err
+	}
+
+	ids := []string{}
+	err = w.Iterate(
+		func(commit *git.Commit) bool {
+			ids = append(ids, commit.Object.Id().String())
+			return true
+		})
+	return ids, err
+}
+
+// CommitBranches returns the local branch for each of the commits which is reachable from only one local branch,
+// commits on several branches, i.e. commits merged into main, do not have a branch
+func CommitBranches(commits []string, wd ...string) (map[string]string, error) {
+	branches := map[string]string{}
+
+	repo, err := openRepository(wd...)
+	if err != nil {
+		return branches, err
+	}
+	defer repo.Free()
+
+	type branchTip struct {
+		name string
+		id   *git.Oid
+	}
+	tips := []branchTip{}
+
+	it, err := repo.NewBranchIterator(git.BranchLocal)
+	if err != nil {
+		return branches, err
+	}
+	defer it.Free()
+	err = it.ForEach(
+		func(b *git.Branch, _ git.BranchType) error {
+			name, err := b.Name()
+			if err != nil {
+				return err
+			}
+			if id := b.Target(); id != nil {
+				tips = append(tips, branchTip{name: name, id: id})
+			}
+			return nil
+		})
+	if err != nil {
+		return branches, err
+	}
+
+	wanted := map[string]bool{}
+	for _, c := range commits {
+		wanted[c] = true
+	}
+
+	for i, tip := range tips {
+		w, err := repo.Walk()
+		if err != nil {
+			return branches, err
+		}
+		if err := w.Push(tip.id); err != nil {
+			w.Free()
+			return branches, err
+		}
+		for j, other := range tips {
+			// branches at the same commit do not hide each other
+			if j == i || other.id.Equal(tip.id) {
+				continue
+			}
+			if err := w.Hide(other.id); err != nil {
+				w.Free()
+				return branches, err
+			}
+		}
+		err = w.Iterate(
+			func(commit *git.Commit) bool {
+				if id := commit.Object.Id().String(); wanted[id] {
+					branches[id] = tip.name
+				}
+				return true
+			})
+		w.Free()
+		if err != nil {
+			return branches, err
+		}
+	}
+	return branches, nil
+}
+
+// peelCommitID returns the id of the commit an object such as an annotated tag points to
+func peelCommitID(obj *git.Object) (*git.Oid, error) {
+	if obj == nil {
+		return nil, fmt.Errorf("Revision not found")
+	}
+	defer obj.Free()
+	commit, err := obj.Peel(git.ObjectCommit)
+	if err != nil {
+		return nil, err
+	}
+	defer commit.Free()
+	return commit.Id(), nil
+}
+
 // Commit contains commit details
 type Commit struct {
 	ID      string
@@ -468,7 +703,9 @@ type CommitNote struct {
 	Email   string
 	When    time.Time
 	Note    string
-	Stats   CommitStats
+	// NoteID is the id of the note's blob, it is blank if the commit does not have a note
+	NoteID string
+	Stats  CommitStats
 }
 
 // ReadNote returns a commit note for the SHA1 commit id
@@ -512,12 +749,160 @@ func ReadNote(commitID string, nameSpace string, calcStats bool, wd ...string) (
 		return CommitNote{}, err
 	}
 
-	var noteTxt string
+	var noteTxt, noteID string
 	n, err = repo.Notes.Read("refs/notes/"+nameSpace, id)
 	if err != nil {
 		noteTxt = ""
 	} else {
 		noteTxt = n.Message()
+		noteID = n.Id().String()
+	}
+
+	stats := CommitStats{}
+	if calcStats {
+		stats, err = DiffParentCommit(commit)
+		if err != nil {
+			return CommitNote{}, err
+		}
+	}
+
+	return CommitNote{
+		ID:      commit.Object.Id().String(),
+		OID:     commit.Object.Id(),
+		Summary: commit.Summary(),
+		Message: commit.Message(),
+		Author:  commit.Author().Name,
+		Email:   commit.Author().Email,
+		When:    commit.Author().When,
+		Note:    noteTxt,
+		NoteID:  noteID,
+		Stats:   stats,
+	}, nil
+}
+
+// NoteReader reads commit notes from a repository that is opened once, notes are found with the
+// notes iterator which is faster than looking up each commit's note when reading many commits.
+// A NoteReader is not safe for concurrent use.
+type NoteReader struct {
+	repo *git.Repository
+	// notes maps a namespace to the note blob ids by commit id
+	notes map[string]map[string]*git.Oid
+}
+
+// NewNoteReader returns a NoteReader for the repository, Free must be called when done
+func NewNoteReader(wd ...string) (*NoteReader, error) {
+	repo, err := openRepository(wd...)
+	if err != nil {
+		return nil, err
+	}
+	return &NoteReader{repo: repo, notes: map[string]map[string]*git.Oid{}}, nil
+}
+
+// Free frees the repository
+func (r *NoteReader) Free() {
+	r.repo.Free()
+}
+
+// noteIDs returns the note blob ids by commit id for the namespace, they are read once
+func (r *NoteReader) noteIDs(nameSpace string) (map[string]*git.Oid, error) {
+	if ids, ok := r.notes[nameSpace]; ok {
+		return ids, nil
+	}
+
+	ids := map[string]*git.Oid{}
+	it, err := r.repo.NewNoteIterator("refs/notes/" + nameSpace)
+	if err != nil {
+		// a namespace without notes does not have a ref
+		if git.IsErrorCode(err, git.ErrNotFound) {
+			r.notes[nameSpace] = ids
+			return ids, nil
+		}
+		return nil, err
+	}
+	defer it.Free()
+
+	for {
+		noteID, commitID, err := it.Next()
+		if err != nil {
+			if git.IsErrorCode(err, git.ErrIterOver) {
+				break
+			}
+			return nil, err
+		}
+		ids[commitID.String()] = noteID
+	}
+	r.notes[nameSpace] = ids
+	return ids, nil
+}
+
+// Ref returns the commit id the namespace's notes ref points to, it is blank if there are no notes
+func (r *NoteReader) Ref(nameSpace string) (string, error) {
+	ref, err := r.repo.References.Lookup("refs/notes/" + nameSpace)
+	if err != nil {
+		if git.IsErrorCode(err, git.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer ref.Free()
+	return ref.Target().String(), nil
+}
+
+// NoteID returns the id of the note's blob for the SHA1 commit id, it is blank if the commit does not have a note
+func (r *NoteReader) NoteID(commitID string, nameSpace string) (string, error) {
+	ids, err := r.noteIDs(nameSpace)
+	if err != nil {
+		return "", err
+	}
+	if noteID, ok := ids[commitID]; ok {
+		return noteID.String(), nil
+	}
+	return "", nil
+}
+
+// Stats returns the changes of the SHA1 commit id compared to its parent
+func (r *NoteReader) Stats(commitID string) (CommitStats, error) {
+	id, err := git.NewOid(commitID)
+	if err != nil {
+		return CommitStats{}, err
+	}
+
+	commit, err := r.repo.LookupCommit(id)
+	if err != nil {
+		return CommitStats{}, err
+	}
+	defer commit.Free()
+
+	return DiffParentCommit(commit)
+}
+
+// Read returns a commit note for the SHA1 commit id
+func (r *NoteReader) Read(commitID string, nameSpace string, calcStats bool) (CommitNote, error) {
+	id, err := git.NewOid(commitID)
+	if err != nil {
+		return CommitNote{}, err
+	}
+
+	commit, err := r.repo.LookupCommit(id)
+	if err != nil {
+		return CommitNote{}, err
+	}
+	defer commit.Free()
+
+	ids, err := r.noteIDs(nameSpace)
+	if err != nil {
+		return CommitNote{}, err
+	}
+
+	var noteTxt, noteID string
+	if oid, ok := ids[id.String()]; ok {
+		blob, err := r.repo.LookupBlob(oid)
+		if err != nil {
+			return CommitNote{}, err
+		}
+		noteTxt = string(blob.Contents())
+		noteID = oid.String()
+		blob.Free()
 	}
 
 	stats := CommitStats{}
@@ -537,6 +922,7 @@ func ReadNote(commitID string, nameSpace string, calcStats bool, wd ...string) (
 		Email:   commit.Author().Email,
 		When:    commit.Author().When,
 		Note:    noteTxt,
+		NoteID:  noteID,
 		Stats:   stats,
 	}, nil
 }
@@ -833,15 +1219,45 @@ func openRepository(wd ...string) (*git.Repository, error) {
 		return nil, err
 	}
 
+	if objectFormat(p) == "sha256" {
+		return nil, ErrSHA256Unsupported
+	}
+
 	repo, err := git.OpenRepository(p)
 	return repo, err
 }
 
+// objectFormat returns the extensions.objectformat setting in the git repo's config, blank is SHA-1
+func objectFormat(gitRepoPath string) string {
+	b, err := ioutil.ReadFile(filepath.Join(gitRepoPath, "config"))
+	if err != nil {
+		return ""
+	}
+	section := ""
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if section == "extensions" && len(kv) == 2 && strings.TrimSpace(kv[0]) == "objectformat" {
+			return strings.TrimSpace(kv[1])
+		}
+	}
+	return ""
+}
+
 var (
 	// ErrHeadUnborn is raised when there are no commits yet in the git repo
 	ErrHeadUnborn = errors.New("Head commit not found")
+	// ErrSHA256Unsupported is raised for git repos and object ids using SHA-256, only SHA-1 object ids are supported
+	ErrSHA256Unsupported = errors.New("SHA-256 repositories are not supported")
 )
 
+// reSHA256ID matches a full SHA-256 object id
+var reSHA256ID = regexp.MustCompile(`\A[0-9a-f]{64}\z`)
+
 func lookupHeadCommit(repo *git.Repository) (*git.Commit, error) {
 
 	headUnborn, err := repo.IsHeadUnborn()
@@ -5,6 +5,7 @@
 package scm
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
@@ -12,6 +13,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/git-time-metric/gtm/util"
 )
@@ -108,6 +110,94 @@ func TestCommitIDs(t *testing.T) {
 	}
 }
 
+func TestRevParse(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	repo.Seed()
+
+	repo.SaveFile("a.go", "", "a")
+	second := repo.Commit(repo.Stage("a.go")).String()
+	repo.SaveFile("b.go", "", "b")
+	third := repo.Commit(repo.Stage("b.go")).String()
+
+	cases := []struct {
+		Revs []string
+		Want []string
+	}{
+		{[]string{"HEAD"}, []string{third}},
+		{[]string{second[:7]}, []string{second}},
+		{[]string{"HEAD~2..HEAD"}, []string{third, second}},
+		{[]string{"HEAD...HEAD~2"}, []string{third, second}},
+		{[]string{"HEAD~1", "HEAD~2..HEAD"}, []string{second, third}},
+	}
+	for _, tc := range cases {
+		commits, err := RevParse(tc.Revs, repo.Workdir())
+		if err != nil {
+			t.Errorf("RevParse(%v), want error nil got %s", tc.Revs, err)
+			continue
+		}
+		if strings.Join(commits, ",") != strings.Join(tc.Want, ",") {
+			t.Errorf("RevParse(%v), want %v got %v", tc.Revs, tc.Want, commits)
+		}
+	}
+
+	if _, err := RevParse([]string{"no-such-branch"}, repo.Workdir()); err == nil {
+		t.Errorf("RevParse(no-such-branch), want error got nil")
+	}
+
+	sha256 := strings.Repeat("0123456789abcdef", 4)
+	if _, err := RevParse([]string{sha256}, repo.Workdir()); err == nil || !strings.Contains(err.Error(), ErrSHA256Unsupported.Error()) {
+		t.Errorf("RevParse(%s), want error %s got %v", sha256, ErrSHA256Unsupported, err)
+	}
+}
+
+func TestObjectFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gtm")
+	util.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+
+	if got := objectFormat(dir); got != "" {
+		t.Errorf("objectFormat() without config, want blank got %s", got)
+	}
+	config := "[core]\n\trepositoryformatversion = 1\n[extensions]\n\tobjectFormat = sha256\n"
+	util.CheckFatal(t, ioutil.WriteFile(filepath.Join(dir, "config"), []byte(config), 0644))
+	if got := objectFormat(dir); got != "sha256" {
+		t.Errorf("objectFormat(), want sha256 got %s", got)
+	}
+}
+
+func TestCommitBranches(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	repo.Seed()
+
+	ref, err := repo.Repo().Head()
+	util.CheckFatal(t, err)
+	seed := ref.Target()
+
+	repo.SaveFile("a.go", "", "a")
+	second := repo.Commit(repo.Stage("a.go"))
+	commit, err := repo.Repo().LookupCommit(second)
+	util.CheckFatal(t, err)
+	defer commit.Free()
+	_, err = repo.Repo().CreateBranch("PROJ-12-feature", commit, false)
+	util.CheckFatal(t, err)
+
+	// move the current branch back so the second commit is only on the feature branch
+	ref, err = repo.Repo().Head()
+	util.CheckFatal(t, err)
+	_, err = ref.SetTarget(seed, "")
+	util.CheckFatal(t, err)
+
+	branches, err := CommitBranches([]string{seed.String(), second.String()}, repo.Workdir())
+	if err != nil {
+		t.Fatalf("CommitBranches(), want error nil got %s", err)
+	}
+	if len(branches) != 1 || branches[second.String()] != "PROJ-12-feature" {
+		t.Errorf("CommitBranches(), want %s on PROJ-12-feature only got %+v", second, branches)
+	}
+}
+
 func TestHeadCommit(t *testing.T) {
 	repo := util.NewTestRepo(t, false)
 	defer repo.Remove()
@@ -196,6 +286,94 @@ func TestNote(t *testing.T) {
 
 }
 
+// commitNotes commits n files with a note for each commit and returns the commit ids
+func commitNotes(tb testing.TB, repo util.TestRepo, n int) []string {
+	ids := []string{}
+	for i := 0; i < n; i++ {
+		file := fmt.Sprintf("file%d.go", i)
+		repo.SaveFile(file, "", "package main\n")
+		id := repo.Commit(repo.Stage(file))
+		util.CheckFatal(tb, CreateNote(fmt.Sprintf("note %d", i), "gtm-data", repo.Workdir()))
+		ids = append(ids, id.String())
+	}
+	return ids
+}
+
+func TestNoteReader(t *testing.T) {
+	repo := util.NewTestRepo(t, false)
+	defer repo.Remove()
+	repo.Seed()
+
+	head, err := HeadCommit(repo.Workdir())
+	util.CheckFatal(t, err)
+	ids := commitNotes(t, repo, 3)
+
+	r, err := NewNoteReader(repo.Workdir())
+	util.CheckFatal(t, err)
+	defer r.Free()
+
+	for i, id := range ids {
+		n, err := r.Read(id, "gtm-data", true)
+		if err != nil {
+			t.Errorf("NoteReader.Read(%s) error, %s", id, err)
+			continue
+		}
+		if want := fmt.Sprintf("note %d", i); n.ID != id || n.Note != want {
+			t.Errorf("NoteReader.Read(%s), want %s %s got %s %s", id, id, want, n.ID, n.Note)
+		}
+		if n.Stats.Insertions != 1 {
+			t.Errorf("NoteReader.Read(%s), want 1 insertion got %d", id, n.Stats.Insertions)
+		}
+	}
+
+	// commits without a note and namespaces without notes have a blank note
+	for _, ns := range []string{"gtm-data", "gtm-local"} {
+		n, err := r.Read(head.ID, ns, false)
+		if err != nil || n.Note != "" || n.ID != head.ID {
+			t.Errorf("NoteReader.Read(%s, %s), want a blank note got %+v, %v", head.ID, ns, n, err)
+		}
+	}
+
+	if _, err := r.Read("0123456789abcdef0123456789abcdef01234567", "gtm-data", false); err == nil {
+		t.Errorf("NoteReader.Read() for a missing commit, want error got nil")
+	}
+}
+
+func BenchmarkReadNote(b *testing.B) {
+	repo := util.NewTestRepo(b, false)
+	defer repo.Remove()
+	ids := commitNotes(b, repo, 100)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, id := range ids {
+			if _, err := ReadNote(id, "gtm-data", false, repo.Workdir()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkNoteReader(b *testing.B) {
+	repo := util.NewTestRepo(b, false)
+	defer repo.Remove()
+	ids := commitNotes(b, repo, 100)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r, err := NewNoteReader(repo.Workdir())
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, id := range ids {
+			if _, err := r.Read(id, "gtm-data", false); err != nil {
+				b.Fatal(err)
+			}
+		}
+		r.Free()
+	}
+}
+
 func TestStatus(t *testing.T) {
 	repo := util.NewTestRepo(t, false)
 	defer repo.Remove()
@@ -475,3 +653,67 @@ func TestPushFetchRemote(t *testing.T) {
 		t.Errorf("ReadNote want message \"%s\", got \"%s\"", noteTxt, note.Note)
 	}
 }
+
+func TestNewCommitLimiterTimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("America/Chicago")
+	util.CheckFatal(t, err)
+
+	limiter, err := NewCommitLimiter(
+		0, "2015-06-30", "2015-06-30", "", "",
+		false, false, false, false, false, false, false, false, time.Sunday, loc)
+	util.CheckFatal(t, err)
+
+	within := []time.Time{
+		time.Date(2015, 6, 30, 0, 0, 0, 0, loc),
+		time.Date(2015, 6, 30, 23, 59, 59, 0, loc),
+		// late evening in Chicago is the next day in UTC
+		time.Date(2015, 7, 1, 2, 0, 0, 0, time.UTC),
+	}
+	for _, tm := range within {
+		if !limiter.DateRange.Within(tm) {
+			t.Errorf("NewCommitLimiter(2015-06-30, %s) want %s within %s", loc, tm, limiter.DateRange)
+		}
+	}
+
+	outside := []time.Time{
+		time.Date(2015, 6, 29, 23, 59, 59, 0, loc),
+		time.Date(2015, 7, 1, 0, 0, 0, 0, loc),
+		time.Date(2015, 6, 30, 4, 0, 0, 0, time.UTC),
+	}
+	for _, tm := range outside {
+		if limiter.DateRange.Within(tm) {
+			t.Errorf("NewCommitLimiter(2015-06-30, %s) want %s not within %s", loc, tm, limiter.DateRange)
+		}
+	}
+}
+
+func TestCommitLimiterTimeSpent(t *testing.T) {
+	limiter, err := NewCommitLimiter(
+		0, "2015-06-01", "2015-06-30", "", "",
+		false, false, false, false, false, false, false, false, time.Sunday, time.UTC)
+	util.CheckFatal(t, err)
+	limiter.TimeSpent = true
+	limiter.Lookback = 7 * 24 * time.Hour
+
+	cases := []struct {
+		Authored time.Time
+		Want     bool
+	}{
+		{time.Date(2015, 6, 15, 0, 0, 0, 0, time.UTC), true},
+		// committed after the date range
+		{time.Date(2015, 7, 20, 0, 0, 0, 0, time.UTC), true},
+		// authored within the lookback before the date range
+		{time.Date(2015, 5, 25, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2015, 5, 24, 23, 59, 59, 0, time.UTC), false},
+	}
+	for _, tc := range cases {
+		if got := limiter.within(tc.Authored); got != tc.Want {
+			t.Errorf("within(%s) with time spent, want %t got %t", tc.Authored, tc.Want, got)
+		}
+	}
+
+	limiter.TimeSpent = false
+	if limiter.within(time.Date(2015, 7, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("within(2015-07-20), want false got true")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scm
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// MailmapFile is the name of the author alias file within the git repo root directory
+const MailmapFile = ".mailmap"
+
+var reMailmapIdent = regexp.MustCompile(`\s*([^<#]*?)\s*<([^>]*)>`)
+
+type identity struct {
+	name  string
+	email string
+}
+
+// Mailmap maps the names and emails authors commit with to one identity
+// using the git .mailmap format, for example
+//
+//	Proper Name <commit@email.xx>
+//	<proper@email.xx> <commit@email.xx>
+//	Proper Name <proper@email.xx> <commit@email.xx>
+//	Proper Name <proper@email.xx> Commit Name <commit@email.xx>
+type Mailmap struct {
+	byEmail     map[string]identity
+	byNameEmail map[identity]identity
+}
+
+// ParseMailmap reads author aliases in the git .mailmap format
+func ParseMailmap(b []byte) Mailmap {
+	m := Mailmap{byEmail: map[string]identity{}, byNameEmail: map[identity]identity{}}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		idents := reMailmapIdent.FindAllStringSubmatch(line, 2)
+		switch len(idents) {
+		case 1:
+			// Proper Name <commit@email.xx>
+			m.byEmail[strings.ToLower(idents[0][2])] = identity{name: idents[0][1]}
+		case 2:
+			proper := identity{name: idents[0][1], email: idents[0][2]}
+			if idents[1][1] == "" {
+				m.byEmail[strings.ToLower(idents[1][2])] = proper
+			} else {
+				m.byNameEmail[identity{name: idents[1][1], email: strings.ToLower(idents[1][2])}] = proper
+			}
+		}
+	}
+	return m
+}
+
+// LoadMailmap reads an author alias file, an empty mailmap is returned if the file does not exist
+func LoadMailmap(mailmapPath string) (Mailmap, error) {
+	b, err := ioutil.ReadFile(mailmapPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Mailmap{}, nil
+		}
+		return Mailmap{}, err
+	}
+	return ParseMailmap(b), nil
+}
+
+// Merge returns the aliases of both mailmaps, aliases in o take precedence
+func (m Mailmap) Merge(o Mailmap) Mailmap {
+	merged := Mailmap{byEmail: map[string]identity{}, byNameEmail: map[identity]identity{}}
+	for _, mm := range []Mailmap{m, o} {
+		for k, v := range mm.byEmail {
+			merged.byEmail[k] = v
+		}
+		for k, v := range mm.byNameEmail {
+			merged.byNameEmail[k] = v
+		}
+	}
+	return merged
+}
+
+// Lookup returns the canonical name and email for an author's name and email
+func (m Mailmap) Lookup(name, email string) (string, string) {
+	proper, ok := m.byNameEmail[identity{name: name, email: strings.ToLower(email)}]
+	if !ok {
+		proper, ok = m.byEmail[strings.ToLower(email)]
+	}
+	if !ok {
+		return name, email
+	}
+	if proper.name != "" {
+		name = proper.name
+	}
+	if proper.email != "" {
+		email = proper.email
+	}
+	return name, email
+}
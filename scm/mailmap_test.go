@@ -0,0 +1,71 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMailmap(t *testing.T) {
+	m := ParseMailmap([]byte(`
+# comments are ignored
+Jane Doe <jane@example.com>
+<john@example.com> <JOHN@work.example.com>
+John Doe <john@example.com> <jdoe@laptop.local>
+Jane Doe <jane@example.com> jd <shared@example.com>
+`))
+
+	cases := []struct {
+		Name, Email         string
+		WantName, WantEmail string
+	}{
+		{"jane", "jane@example.com", "Jane Doe", "jane@example.com"},
+		{"John", "john@work.example.com", "John", "john@example.com"},
+		{"johnny", "jdoe@laptop.local", "John Doe", "john@example.com"},
+		{"jd", "shared@example.com", "Jane Doe", "jane@example.com"},
+		{"someone", "shared@example.com", "someone", "shared@example.com"},
+		{"Bob", "bob@example.com", "Bob", "bob@example.com"},
+	}
+
+	for _, tc := range cases {
+		name, email := m.Lookup(tc.Name, tc.Email)
+		if name != tc.WantName || email != tc.WantEmail {
+			t.Errorf("Lookup(%s, %s), want %s <%s> got %s <%s>", tc.Name, tc.Email, tc.WantName, tc.WantEmail, name, email)
+		}
+	}
+
+	merged := Mailmap{}.Merge(ParseMailmap([]byte("Bobby <bob@example.com>"))).Merge(m)
+	if name, _ := merged.Lookup("Bob", "bob@example.com"); name != "Bobby" {
+		t.Errorf("Merge(), want Bobby got %s", name)
+	}
+	if name, _ := merged.Lookup("jane", "jane@example.com"); name != "Jane Doe" {
+		t.Errorf("Merge(), want Jane Doe got %s", name)
+	}
+
+	if name, email := (Mailmap{}).Lookup("Bob", "bob@example.com"); name != "Bob" || email != "bob@example.com" {
+		t.Errorf("Lookup() with empty mailmap, want Bob <bob@example.com> got %s <%s>", name, email)
+	}
+}
+
+func TestCommitLimiterMatchAuthor(t *testing.T) {
+	limiter, err := NewCommitLimiter(
+		0, "", "", "john@example.com", "",
+		false, false, false, false, false, false, false, false, time.Sunday, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter.Mailmap = ParseMailmap([]byte("John Doe <john@example.com> <jdoe@laptop.local>"))
+
+	if !limiter.matchAuthor("John", "john@example.com") {
+		t.Errorf("matchAuthor(John, john@example.com), want true got false")
+	}
+	if !limiter.matchAuthor("johnny", "jdoe@laptop.local") {
+		t.Errorf("matchAuthor(johnny, jdoe@laptop.local), want true got false")
+	}
+	if limiter.matchAuthor("Jane", "jane@example.com") {
+		t.Errorf("matchAuthor(Jane, jane@example.com), want false got true")
+	}
+}
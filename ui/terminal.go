@@ -0,0 +1,113 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	altScreen  = "\x1b[?1049h"
+	mainScreen = "\x1b[?1049l"
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+	home       = "\x1b[H"
+	clearLine  = "\x1b[K"
+)
+
+// escapeKeys are the keys sent as escape sequences after the escape character
+var escapeKeys = map[string]Key{
+	"[A": KeyUp, "[B": KeyDown, "[C": KeyRight, "[D": KeyLeft,
+	"OA": KeyUp, "OB": KeyDown, "OC": KeyRight, "OD": KeyLeft,
+	"[5~": KeyPageUp, "[6~": KeyPageDown,
+	"[H": KeyHome, "[1~": KeyHome, "[7~": KeyHome, "OH": KeyHome,
+	"[F": KeyEnd, "[4~": KeyEnd, "[8~": KeyEnd, "OF": KeyEnd,
+}
+
+// Run shows the UI in the terminal until the user quits
+func Run(m *Model, in, out *os.File) error {
+	inFd, outFd := int(in.Fd()), int(out.Fd())
+	state, err := terminal.MakeRaw(inFd)
+	if err != nil {
+		return err
+	}
+	defer terminal.Restore(inFd, state)
+
+	fmt.Fprint(out, altScreen+hideCursor)
+	defer fmt.Fprint(out, showCursor+mainScreen)
+
+	r := bufio.NewReader(in)
+	for {
+		if w, h, err := terminal.GetSize(outFd); err == nil {
+			m.Resize(w, h)
+		}
+		draw(out, m.Render())
+
+		k, err := readKey(r)
+		if err != nil {
+			return err
+		}
+		if m.Handle(k) {
+			return nil
+		}
+	}
+}
+
+// draw writes the lines over the previous screen
+func draw(w io.Writer, lines []string) {
+	fmt.Fprint(w, home+strings.Join(lines, clearLine+"\r\n")+clearLine)
+}
+
+// readKey returns the next key press, an escape followed by nothing buffered is the escape key
+func readKey(r *bufio.Reader) (Key, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	switch c {
+	case '\r', '\n':
+		return KeyEnter, nil
+	case 127, '\b':
+		return KeyBackspace, nil
+	case 3:
+		return KeyCtrlC, nil
+	case '\x1b':
+		if r.Buffered() == 0 {
+			return KeyEsc, nil
+		}
+		seq := ""
+		for r.Buffered() > 0 {
+			b, err := r.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			seq += string(b)
+			// a sequence ends with a letter or ~ after its first character
+			if len(seq) > 1 && (b == '~' || (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z')) {
+				break
+			}
+		}
+		if k, ok := escapeKeys[seq]; ok {
+			return k, nil
+		}
+		return KeyEsc, nil
+	}
+
+	if err := r.UnreadByte(); err != nil {
+		return "", err
+	}
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	return Key(string(ch)), nil
+}
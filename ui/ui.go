@@ -0,0 +1,552 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package ui is a keyboard-driven terminal interface for browsing projects,
+// commits and reports.
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/git-time-metric/gtm/report"
+	"github.com/git-time-metric/gtm/util"
+)
+
+// DateRanges are the date ranges the UI cycles through, all is every commit
+var DateRanges = []string{"today", "yesterday", "this-week", "last-week", "this-month", "last-month", "this-year", "last-year", "all"}
+
+// Formats are the report formats the UI cycles through
+var Formats = []string{"summary", "project", "commits", "files", "timeline-hours", "timeline-commits", "authors", "dirs", "categories", "issues", "sessions", "focus", "timesheet"}
+
+// Source provides the time data browsed in the UI
+type Source interface {
+	// Projects returns the projects with any of the tags, all projects if there are no tags
+	Projects(tags []string) ([]string, error)
+	// Commits returns a project's commits within the date range, newest first
+	Commits(projPath, dateRange string) ([]report.CommitData, error)
+	// Commit returns the files and timeline for a commit
+	Commit(projPath, commitID string) (string, error)
+	// Report returns a report for the projects within the date range
+	Report(format string, projPaths []string, dateRange string) (string, error)
+	// Status returns the pending time for the projects
+	Status(projPaths []string) (string, error)
+}
+
+// Key is a key press, either a name such as up or enter or the character typed
+type Key string
+
+// Keys which are not characters
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyPageUp    Key = "pgup"
+	KeyPageDown  Key = "pgdn"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyEnter     Key = "enter"
+	KeyEsc       Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyCtrlC     Key = "ctrl-c"
+)
+
+const (
+	inverse = "\x1b[7m"
+	bold    = "\x1b[1m"
+	reset   = "\x1b[0m"
+)
+
+type view int
+
+const (
+	projectsView view = iota
+	commitsView
+	commitView
+	reportView
+	statusView
+	helpView
+)
+
+// allProjects is the first row of the projects view
+const allProjects = "(all projects)"
+
+const helpText = `Keys
+
+  up, down, k, j         Move the selection or scroll
+  pgup, pgdn, home, end  Move or scroll by page or to the start or end
+  enter, right, l        Open the selected project or commit
+  esc, left, h           Go back
+  v                      Report for the selected project or all projects
+  f, F                   Next or previous report format
+  d, D                   Next or previous date range
+  t                      Edit the project tags, blank is all projects
+  s                      Pending time for the selected project or all projects
+  r                      Reload
+  ?                      Help
+  q                      Quit`
+
+// screen is a view in the navigation stack
+type screen struct {
+	view     view
+	title    string
+	projects []string
+	commitID string
+	commits  []report.CommitData
+	lines    []string
+	selected int
+	offset   int
+	// stale screens are reloaded when they are shown again
+	stale bool
+}
+
+// Model is the state of the UI, it is updated by Handle and drawn by Render
+type Model struct {
+	src       Source
+	screens   []*screen
+	tags      []string
+	dateRange int
+	format    int
+	width     int
+	height    int
+	editing   bool
+	input     string
+	message   string
+}
+
+// NewModel returns a model showing the projects from src
+func NewModel(src Source, tags []string) (*Model, error) {
+	m := &Model{src: src, tags: tags, dateRange: 2, width: 80, height: 24}
+	s := &screen{view: projectsView, title: "Projects"}
+	if err := m.load(s); err != nil {
+		return nil, err
+	}
+	m.screens = []*screen{s}
+	return m, nil
+}
+
+// DateRange returns the current date range
+func (m *Model) DateRange() string {
+	return DateRanges[m.dateRange]
+}
+
+// Format returns the current report format
+func (m *Model) Format() string {
+	return Formats[m.format]
+}
+
+// Resize sets the size of the terminal
+func (m *Model) Resize(width, height int) {
+	if width > 0 {
+		m.width = width
+	}
+	if height > 2 {
+		m.height = height
+	}
+	m.scroll(m.top(), 0)
+}
+
+func (m *Model) top() *screen {
+	return m.screens[len(m.screens)-1]
+}
+
+// bodyHeight is the number of lines between the header and the footer
+func (m *Model) bodyHeight() int {
+	return m.height - 2
+}
+
+// load retrieves a screen's data from the source
+func (m *Model) load(s *screen) error {
+	var (
+		out string
+		err error
+	)
+	switch s.view {
+	case projectsView:
+		var projects []string
+		if projects, err = m.src.Projects(m.tags); err != nil {
+			return err
+		}
+		s.projects = projects
+	case commitsView:
+		var commits []report.CommitData
+		if commits, err = m.src.Commits(s.projects[0], m.DateRange()); err != nil {
+			return err
+		}
+		s.commits = commits
+	case commitView:
+		out, err = m.src.Commit(s.projects[0], s.commitID)
+	case reportView:
+		s.title = fmt.Sprintf("%s report for %s", m.Format(), projectsTitle(s.projects))
+		out, err = m.src.Report(m.Format(), s.projects, m.DateRange())
+	case statusView:
+		out, err = m.src.Status(s.projects)
+	case helpView:
+		out = helpText
+	}
+	if err != nil {
+		return err
+	}
+	if s.view != projectsView && s.view != commitsView {
+		s.lines = textLines(out)
+	}
+	s.stale = false
+	if n := s.rows(); s.selected >= n {
+		s.selected = max(n-1, 0)
+	}
+	return nil
+}
+
+// textLines splits text into lines without leading and trailing blank lines
+func textLines(s string) []string {
+	s = strings.Trim(strings.Replace(s, "\r\n", "\n", -1), "\n")
+	if strings.TrimSpace(s) == "" {
+		return []string{"No time data"}
+	}
+	return strings.Split(strings.Replace(s, "\t", "    ", -1), "\n")
+}
+
+func projectsTitle(projects []string) string {
+	if len(projects) == 1 {
+		return filepath.Base(projects[0])
+	}
+	return "all projects"
+}
+
+// rows returns the number of selectable rows or lines of text
+func (s *screen) rows() int {
+	switch s.view {
+	case projectsView:
+		return len(s.projects) + 1
+	case commitsView:
+		return len(s.commits)
+	default:
+		return len(s.lines)
+	}
+}
+
+// isList returns true if rows are selected instead of scrolled
+func (s *screen) isList() bool {
+	return s.view == projectsView || s.view == commitsView
+}
+
+// selectedProjects returns the projects for the selection, all projects for the first row of the projects view
+func (m *Model) selectedProjects() []string {
+	s := m.top()
+	if s.view == projectsView && s.selected > 0 {
+		return []string{s.projects[s.selected-1]}
+	}
+	return s.projects
+}
+
+// push loads and shows a screen
+func (m *Model) push(s *screen) {
+	if err := m.load(s); err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.screens = append(m.screens, s)
+}
+
+// pop goes back to the previous screen
+func (m *Model) pop() {
+	if len(m.screens) == 1 {
+		return
+	}
+	m.screens = m.screens[:len(m.screens)-1]
+	if s := m.top(); s.stale {
+		m.reload(s)
+	}
+}
+
+func (m *Model) reload(s *screen) {
+	if err := m.load(s); err != nil {
+		m.message = err.Error()
+	}
+}
+
+// changed reloads the shown screen and marks the others stale after the date range or format changes
+func (m *Model) changed() {
+	for _, s := range m.screens {
+		s.stale = true
+	}
+	m.reload(m.top())
+}
+
+// scroll moves the selection of a list or the offset of text by n rows
+func (m *Model) scroll(s *screen, n int) {
+	rows, height := s.rows(), m.bodyHeight()
+	if s.isList() {
+		s.selected = clamp(s.selected+n, 0, rows-1)
+		switch {
+		case s.selected < s.offset:
+			s.offset = s.selected
+		case s.selected >= s.offset+height:
+			s.offset = s.selected - height + 1
+		}
+	} else {
+		s.offset += n
+	}
+	s.offset = clamp(s.offset, 0, rows-height)
+}
+
+// Handle updates the model for a key press, it returns true if the UI should quit
+func (m *Model) Handle(k Key) bool {
+	m.message = ""
+	if m.editing {
+		m.edit(k)
+		return false
+	}
+
+	s := m.top()
+	switch k {
+	case "q", KeyCtrlC:
+		return true
+	case KeyUp, "k":
+		m.scroll(s, -1)
+	case KeyDown, "j":
+		m.scroll(s, 1)
+	case KeyPageUp:
+		m.scroll(s, -m.bodyHeight())
+	case KeyPageDown, " ":
+		m.scroll(s, m.bodyHeight())
+	case KeyHome, "g":
+		m.scroll(s, -s.rows())
+	case KeyEnd, "G":
+		m.scroll(s, s.rows())
+	case KeyEsc, KeyLeft, KeyBackspace, "h":
+		m.pop()
+	case KeyEnter, KeyRight, "l":
+		m.open(s)
+	case "v":
+		m.push(&screen{view: reportView, projects: m.selectedProjects()})
+	case "s":
+		projects := m.selectedProjects()
+		m.push(&screen{view: statusView, title: "Pending time for " + projectsTitle(projects), projects: projects})
+	case "f", "F":
+		m.format = next(m.format, len(Formats), k == "f")
+		if s.view == reportView {
+			m.changed()
+		} else {
+			m.message = "Format " + m.Format() + ", press v for the report"
+		}
+	case "d", "D":
+		m.dateRange = next(m.dateRange, len(DateRanges), k == "d")
+		m.changed()
+	case "t":
+		m.editing = true
+		m.input = strings.Join(m.tags, ",")
+	case "r":
+		m.changed()
+	case "?":
+		m.push(&screen{view: helpView, title: "Help"})
+	}
+	return false
+}
+
+// open shows the selected project's commits or the selected commit
+func (m *Model) open(s *screen) {
+	switch {
+	case s.view == projectsView && s.selected == 0:
+		m.push(&screen{view: reportView, projects: s.projects})
+	case s.view == projectsView:
+		p := s.projects[s.selected-1]
+		m.push(&screen{view: commitsView, title: "Commits for " + filepath.Base(p), projects: []string{p}})
+	case s.view == commitsView && len(s.commits) > 0:
+		c := s.commits[s.selected]
+		title := fmt.Sprintf("%s %s", shortHash(c.Hash), c.Subject)
+		m.push(&screen{view: commitView, title: title, projects: s.projects, commitID: c.Hash})
+	}
+}
+
+// edit updates the tags being typed
+func (m *Model) edit(k Key) {
+	switch k {
+	case KeyEnter:
+		m.editing = false
+		m.tags = []string{}
+		for _, t := range strings.Split(m.input, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				m.tags = append(m.tags, t)
+			}
+		}
+		// projects change with the tags so start over
+		s := &screen{view: projectsView, title: "Projects"}
+		if err := m.load(s); err != nil {
+			m.message = err.Error()
+			return
+		}
+		m.screens = []*screen{s}
+	case KeyEsc, KeyCtrlC:
+		m.editing = false
+	case KeyBackspace:
+		if _, size := utf8.DecodeLastRuneInString(m.input); size > 0 {
+			m.input = m.input[:len(m.input)-size]
+		}
+	default:
+		if utf8.RuneCountInString(string(k)) == 1 {
+			m.input += string(k)
+		}
+	}
+}
+
+// Render returns the lines to draw, a header, the shown screen and a footer
+func (m *Model) Render() []string {
+	s := m.top()
+	tags := "all"
+	if len(m.tags) > 0 {
+		tags = strings.Join(m.tags, ",")
+	}
+	header := fmt.Sprintf(" gtm │ %s │ %s │ format %s │ tags %s", s.title, m.DateRange(), m.Format(), tags)
+	lines := []string{inverse + pad(header, m.width) + reset}
+
+	height := m.bodyHeight()
+	rows := s.rows()
+	for i := s.offset; i < s.offset+height && i < rows; i++ {
+		line := m.row(s, i)
+		if s.isList() && i == s.selected {
+			line = inverse + pad(line, m.width) + reset
+		} else {
+			line = clip(line, m.width)
+		}
+		lines = append(lines, line)
+	}
+	if rows == 0 {
+		lines = append(lines, " No commits")
+	}
+	for len(lines) < height+1 {
+		lines = append(lines, "")
+	}
+
+	var footer string
+	switch {
+	case m.editing:
+		footer = bold + clip(" Tags (comma separated): "+m.input+"_", m.width) + reset
+	case m.message != "":
+		footer = bold + clip(" "+m.message, m.width) + reset
+	default:
+		footer = clip(" enter open │ esc back │ v report │ f format │ d dates │ t tags │ s pending │ ? help │ q quit", m.width)
+	}
+	return append(lines, footer)
+}
+
+// row returns the text for a row of a screen
+func (m *Model) row(s *screen, i int) string {
+	switch s.view {
+	case projectsView:
+		if i == 0 {
+			return " " + allProjects
+		}
+		p := s.projects[i-1]
+		return fmt.Sprintf(" %-20s %s", filepath.Base(p), p)
+	case commitsView:
+		c := s.commits[i]
+		return fmt.Sprintf(" %s  %s  %s  %s", c.Date.Format("Mon Jan 02 15:04"), shortHash(c.Hash),
+			util.LeftPad2Len(util.FormatDuration(c.Seconds), " ", 13), c.Subject)
+	default:
+		return s.lines[i]
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+// pad clips or pads a line with spaces to the width
+func pad(s string, width int) string {
+	s = clip(s, width)
+	if n := visibleLen(s); n < width {
+		s += strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// clip shortens a line to the width, ANSI escape sequences are kept but not counted
+func clip(s string, width int) string {
+	b := new(bytes.Buffer)
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			j := i + 1
+			if j < len(s) && s[j] == '[' {
+				for j++; j < len(s) && (s[j] < '@' || s[j] > '~'); j++ {
+				}
+			}
+			j = min(j+1, len(s))
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if n == width {
+			// close any color left open by the clipped text
+			b.WriteString(reset)
+			break
+		}
+		b.WriteRune(r)
+		n++
+		i += size
+	}
+	return b.String()
+}
+
+// visibleLen returns the number of characters without ANSI escape sequences
+func visibleLen(s string) int {
+	return utf8.RuneCountInString(stripANSI(s))
+}
+
+func stripANSI(s string) string {
+	b := new(bytes.Buffer)
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '[' {
+			for i += 2; i < len(s) && (s[i] < '@' || s[i] > '~'); i++ {
+			}
+		} else {
+			i++
+		}
+	}
+	return b.String()
+}
+
+func next(i, n int, forward bool) int {
+	if forward {
+		return (i + 1) % n
+	}
+	return (i + n - 1) % n
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
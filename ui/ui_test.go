@@ -0,0 +1,232 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/git-time-metric/gtm/report"
+)
+
+// fakeSource records the requests made by the UI
+type fakeSource struct {
+	requests []string
+}
+
+func (f *fakeSource) Projects(tags []string) ([]string, error) {
+	f.requests = append(f.requests, "projects "+strings.Join(tags, ","))
+	if len(tags) > 0 {
+		return []string{"/src/tagged"}, nil
+	}
+	return []string{"/src/one", "/src/two"}, nil
+}
+
+func (f *fakeSource) Commits(projPath, dateRange string) ([]report.CommitData, error) {
+	f.requests = append(f.requests, fmt.Sprintf("commits %s %s", projPath, dateRange))
+	return []report.CommitData{
+		{Hash: "aaaaaaaaaaaa", Subject: "Second", Seconds: 120, Date: time.Date(2015, 7, 1, 10, 0, 0, 0, time.UTC)},
+		{Hash: "bbbbbbbbbbbb", Subject: "First", Seconds: 60, Date: time.Date(2015, 6, 30, 10, 0, 0, 0, time.UTC)},
+	}, nil
+}
+
+func (f *fakeSource) Commit(projPath, commitID string) (string, error) {
+	f.requests = append(f.requests, fmt.Sprintf("commit %s %s", projPath, commitID))
+	return "\nfiles of " + commitID + "\n", nil
+}
+
+func (f *fakeSource) Report(format string, projPaths []string, dateRange string) (string, error) {
+	f.requests = append(f.requests, fmt.Sprintf("report %s %s %s", format, strings.Join(projPaths, ","), dateRange))
+	lines := []string{}
+	for i := 0; i < 50; i++ {
+		lines = append(lines, fmt.Sprintf("%s line %d", format, i))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (f *fakeSource) Status(projPaths []string) (string, error) {
+	f.requests = append(f.requests, "status "+strings.Join(projPaths, ","))
+	return "pending", nil
+}
+
+func (f *fakeSource) last() string {
+	if len(f.requests) == 0 {
+		return ""
+	}
+	return f.requests[len(f.requests)-1]
+}
+
+func newTestModel(t *testing.T) (*Model, *fakeSource) {
+	src := &fakeSource{}
+	m, err := NewModel(src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Resize(60, 10)
+	return m, src
+}
+
+func handle(m *Model, keys ...Key) {
+	for _, k := range keys {
+		m.Handle(k)
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	m, src := newTestModel(t)
+
+	handle(m, KeyDown, KeyDown, KeyEnter)
+	if want := "commits /src/two this-week"; src.last() != want {
+		t.Errorf("Handle(enter) on a project, want %s got %s", want, src.last())
+	}
+
+	handle(m, "j", KeyEnter)
+	if want := "commit /src/two bbbbbbbbbbbb"; src.last() != want {
+		t.Errorf("Handle(enter) on a commit, want %s got %s", want, src.last())
+	}
+	if lines := m.Render(); !strings.Contains(lines[0], "bbbbbbbb First") || lines[1] != "files of bbbbbbbbbbbb" {
+		t.Errorf("Render() commit, want header with the commit and its files got\n%s", strings.Join(lines, "\n"))
+	}
+
+	// going back keeps the selection
+	handle(m, KeyEsc)
+	if s := m.top(); s.view != commitsView || s.selected != 1 {
+		t.Errorf("Handle(esc), want commits view with the second commit selected got %d %d", s.view, s.selected)
+	}
+	handle(m, KeyEsc, KeyEsc, KeyEsc)
+	if len(m.screens) != 1 {
+		t.Errorf("Handle(esc) on the projects view, want 1 screen got %d", len(m.screens))
+	}
+
+	if quit := m.Handle("q"); !quit {
+		t.Errorf("Handle(q), want quit got false")
+	}
+}
+
+func TestReportFormatsAndDateRanges(t *testing.T) {
+	m, src := newTestModel(t)
+
+	handle(m, KeyEnter)
+	if want := "report summary /src/one,/src/two this-week"; src.last() != want {
+		t.Errorf("Handle(enter) on all projects, want %s got %s", want, src.last())
+	}
+
+	handle(m, "f")
+	if want := "report project /src/one,/src/two this-week"; src.last() != want {
+		t.Errorf("Handle(f), want %s got %s", want, src.last())
+	}
+	handle(m, "F", "F")
+	if want := "report timesheet /src/one,/src/two this-week"; src.last() != want {
+		t.Errorf("Handle(F, F), want %s got %s", want, src.last())
+	}
+
+	handle(m, "d")
+	if want := "report timesheet /src/one,/src/two last-week"; src.last() != want {
+		t.Errorf("Handle(d), want %s got %s", want, src.last())
+	}
+
+	// the projects view is stale after the date range changed
+	handle(m, KeyEsc)
+	if want := "projects "; src.last() != want {
+		t.Errorf("Handle(esc) after date range changed, want %s got %s", want, src.last())
+	}
+
+	handle(m, KeyDown, "v")
+	if want := "report timesheet /src/one last-week"; src.last() != want {
+		t.Errorf("Handle(v) on a project, want %s got %s", want, src.last())
+	}
+}
+
+func TestScroll(t *testing.T) {
+	m, _ := newTestModel(t)
+	handle(m, "v")
+
+	// 10 lines less the header and footer
+	handle(m, KeyPageDown)
+	if lines := m.Render(); lines[1] != "summary line 8" || len(lines) != 10 {
+		t.Errorf("Handle(pgdn), want summary line 8 first got %s, %d lines", lines[1], len(lines))
+	}
+	handle(m, KeyEnd)
+	if lines := m.Render(); lines[8] != "summary line 49" {
+		t.Errorf("Handle(end), want summary line 49 last got %s", lines[8])
+	}
+	handle(m, "k", KeyHome)
+	if lines := m.Render(); lines[1] != "summary line 0" {
+		t.Errorf("Handle(home), want summary line 0 first got %s", lines[1])
+	}
+}
+
+func TestTagsAndStatus(t *testing.T) {
+	m, src := newTestModel(t)
+
+	handle(m, KeyDown, KeyEnter, "t")
+	for _, r := range "a,b" {
+		m.Handle(Key(string(r)))
+	}
+	if lines := m.Render(); !strings.Contains(lines[len(lines)-1], "Tags (comma separated): a,b_") {
+		t.Errorf("Handle(t), want tags prompt got %s", lines[len(lines)-1])
+	}
+	handle(m, KeyBackspace, "x", KeyEnter)
+	if want := "projects a,x"; src.last() != want {
+		t.Errorf("Handle(enter) after editing tags, want %s got %s", want, src.last())
+	}
+	if len(m.screens) != 1 || !strings.Contains(m.Render()[0], "tags a,x") {
+		t.Errorf("Handle(enter) after editing tags, want projects view with tags a,x got %s", m.Render()[0])
+	}
+
+	handle(m, "s")
+	if want := "status /src/tagged"; src.last() != want {
+		t.Errorf("Handle(s), want %s got %s", want, src.last())
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		keys []Key
+	}{
+		{"\x1b[A\x1b[B\x1bOC\x1b[D", []Key{KeyUp, KeyDown, KeyRight, KeyLeft}},
+		{"\x1b[5~\x1b[6~\x1b[H\x1b[4~", []Key{KeyPageUp, KeyPageDown, KeyHome, KeyEnd}},
+		{"\rq\x7f\x03", []Key{KeyEnter, "q", KeyBackspace, KeyCtrlC}},
+		{"é\x1b", []Key{"é", KeyEsc}},
+	}
+
+	for _, tc := range tests {
+		r := bufio.NewReader(strings.NewReader(tc.in))
+		for _, want := range tc.keys {
+			got, err := readKey(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("readKey(%q), want %s got %s", tc.in, want, got)
+			}
+		}
+	}
+}
+
+func TestClip(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"abc", 5, "abc"},
+		{"abcdef", 3, "abc" + reset},
+		{"\x1b[1mabc\x1b[0mdef", 4, "\x1b[1mabc\x1b[0md" + reset},
+		{"héllo", 2, "hé" + reset},
+	}
+	for _, tc := range tests {
+		if got := clip(tc.in, tc.width); got != tc.want {
+			t.Errorf("clip(%q, %d), want %q got %q", tc.in, tc.width, tc.want, got)
+		}
+	}
+	if got := visibleLen(pad("\x1b[1mab", 6)); got != 6 {
+		t.Errorf("pad(), want 6 visible characters got %d", got)
+	}
+}
@@ -6,6 +6,7 @@ package util
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jinzhu/now"
@@ -46,15 +47,73 @@ func (d DateRange) Within(t time.Time) bool {
 
 }
 
+// Previous returns the period of the same length immediately before the date range, whole calendar
+// months are shifted by months, i.e. last month for this month and last year for this year
+func (d DateRange) Previous() DateRange {
+	start, end := d.Start, d.End.Add(time.Nanosecond)
+	months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+	if months > 0 && start.Equal(now.New(start).BeginningOfMonth()) && end.Equal(start.AddDate(0, months, 0)) {
+		return DateRange{Start: start.AddDate(0, -months, 0), End: d.Start.Add(-time.Nanosecond)}
+	}
+	// days are added to keep the time of day across daylight saving time changes
+	days := int(end.Sub(start).Hours()/24 + 0.5)
+	if days > 0 && end.Equal(start.AddDate(0, 0, days)) {
+		return DateRange{Start: start.AddDate(0, 0, -days), End: d.Start.Add(-time.Nanosecond)}
+	}
+	return DateRange{Start: start.Add(-end.Sub(start)), End: d.Start.Add(-time.Nanosecond)}
+}
+
+// ParseTimeZone returns the location for a time zone name
+// A blank name or local returns the system's time zone
+func ParseTimeZone(name string) (*time.Location, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "local":
+		return time.Local, nil
+	case "utc":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(name))
+	if err != nil {
+		return nil, fmt.Errorf("Time zone %s is not valid, %s", name, err)
+	}
+	return loc, nil
+}
+
+// ParseWeekday returns the day of the week for a name, i.e. monday or mon
+func ParseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		day := strings.ToLower(d.String())
+		if name == day || name == day[:3] {
+			return d, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("Day of the week %s is not valid", name)
+}
+
+// beginningOfWeek returns the start of the week for t, weeks start on weekStart
+func beginningOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	start := now.New(t).BeginningOfDay()
+	return start.AddDate(0, 0, -((int(start.Weekday()) - int(weekStart) + 7) % 7))
+}
+
+// nowIn returns the current time in the optional location
+func nowIn(loc ...*time.Location) time.Time {
+	if len(loc) > 0 && loc[0] != nil {
+		return Now().In(loc[0])
+	}
+	return Now()
+}
+
 // AfterNow returns a date range ending n days in the past
-func AfterNow(n int) DateRange {
-	end := now.New(Now()).EndOfDay().AddDate(0, 0, -n)
+func AfterNow(n int, loc ...*time.Location) DateRange {
+	end := now.New(nowIn(loc...)).EndOfDay().AddDate(0, 0, -n)
 	return DateRange{End: end}
 }
 
 // TodayRange returns a date range for today
-func TodayRange() DateRange {
-	now := now.New(Now())
+func TodayRange(loc ...*time.Location) DateRange {
+	now := now.New(nowIn(loc...))
 
 	start := now.BeginningOfDay()
 	end := now.EndOfDay()
@@ -63,8 +122,8 @@ func TodayRange() DateRange {
 }
 
 // YesterdayRange returns a date range for yesterday
-func YesterdayRange() DateRange {
-	now := now.New(Now())
+func YesterdayRange(loc ...*time.Location) DateRange {
+	now := now.New(nowIn(loc...))
 
 	start := now.BeginningOfDay().AddDate(0, 0, -1)
 	end := start.AddDate(0, 0, 1).Add(-time.Nanosecond)
@@ -72,29 +131,25 @@ func YesterdayRange() DateRange {
 	return DateRange{Start: start, End: end}
 }
 
-// ThisWeekRange returns a date range for this week
-func ThisWeekRange() DateRange {
-	now := now.New(Now())
-
-	start := now.BeginningOfWeek()
-	end := now.EndOfWeek()
+// ThisWeekRange returns a date range for this week, weeks start on weekStart
+func ThisWeekRange(weekStart time.Weekday, loc ...*time.Location) DateRange {
+	start := beginningOfWeek(nowIn(loc...), weekStart)
+	end := start.AddDate(0, 0, 7).Add(-time.Nanosecond)
 
 	return DateRange{End: end, Start: start}
 }
 
-// LastWeekRange returns a date for last week
-func LastWeekRange() DateRange {
-	now := now.New(Now())
-
-	start := now.BeginningOfWeek().AddDate(0, 0, -7)
+// LastWeekRange returns a date for last week, weeks start on weekStart
+func LastWeekRange(weekStart time.Weekday, loc ...*time.Location) DateRange {
+	start := beginningOfWeek(nowIn(loc...), weekStart).AddDate(0, 0, -7)
 	end := start.AddDate(0, 0, 7).Add(-time.Nanosecond)
 
 	return DateRange{End: end, Start: start}
 }
 
 // ThisMonthRange returns a date range for this month
-func ThisMonthRange() DateRange {
-	now := now.New(Now())
+func ThisMonthRange(loc ...*time.Location) DateRange {
+	now := now.New(nowIn(loc...))
 
 	start := now.BeginningOfMonth()
 	end := now.EndOfMonth()
@@ -103,8 +158,8 @@ func ThisMonthRange() DateRange {
 }
 
 // LastMonthRange returns a date range for last month
-func LastMonthRange() DateRange {
-	now := now.New(Now())
+func LastMonthRange(loc ...*time.Location) DateRange {
+	now := now.New(nowIn(loc...))
 
 	start := now.BeginningOfMonth().AddDate(0, -1, 0)
 	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
@@ -113,8 +168,8 @@ func LastMonthRange() DateRange {
 }
 
 // ThisYearRange returns a date range for this year
-func ThisYearRange() DateRange {
-	now := now.New(Now())
+func ThisYearRange(loc ...*time.Location) DateRange {
+	now := now.New(nowIn(loc...))
 
 	start := now.BeginningOfYear()
 	end := now.EndOfYear()
@@ -123,11 +178,58 @@ func ThisYearRange() DateRange {
 }
 
 // LastYearRange returns a date range for last year
-func LastYearRange() DateRange {
-	now := now.New(Now())
+func LastYearRange(loc ...*time.Location) DateRange {
+	now := now.New(nowIn(loc...))
 
 	start := now.BeginningOfYear().AddDate(-1, 0, 0)
 	end := start.AddDate(1, 0, 0).Add(-time.Nanosecond)
 
 	return DateRange{End: end, Start: start}
 }
+
+// Periods for grouping dates
+const (
+	PeriodDay     = "day"
+	PeriodWeek    = "week"
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+	PeriodYear    = "year"
+)
+
+// Periods are the valid periods for grouping dates
+var Periods = []string{PeriodDay, PeriodWeek, PeriodMonth, PeriodQuarter, PeriodYear}
+
+// PeriodStart returns the start of the period containing t in t's time zone, weeks start on weekStart
+func PeriodStart(period string, t time.Time, weekStart time.Weekday) time.Time {
+	n := now.New(t)
+	switch period {
+	case PeriodWeek:
+		return beginningOfWeek(t, weekStart)
+	case PeriodMonth:
+		return n.BeginningOfMonth()
+	case PeriodQuarter:
+		return n.BeginningOfQuarter()
+	case PeriodYear:
+		return n.BeginningOfYear()
+	default:
+		return n.BeginningOfDay()
+	}
+}
+
+// PeriodLabel returns the name of the period containing t, i.e. Mon Jan 02 2006,
+// Week of Jan 02 2006, January 2006, 2006 Q1 or 2006
+func PeriodLabel(period string, t time.Time, weekStart time.Weekday) string {
+	start := PeriodStart(period, t, weekStart)
+	switch period {
+	case PeriodWeek:
+		return start.Format("Week of Jan 02 2006")
+	case PeriodMonth:
+		return start.Format("January 2006")
+	case PeriodQuarter:
+		return fmt.Sprintf("%d Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	case PeriodYear:
+		return start.Format("2006")
+	default:
+		return start.Format("Mon Jan 02 2006")
+	}
+}
@@ -15,8 +15,8 @@ import (
 func printDates() {
 	fmt.Printf("%+10s %s\n", "Today", TodayRange())
 	fmt.Printf("%+10s %s\n", "Yesterday", YesterdayRange())
-	fmt.Printf("%+10s %s\n", "ThisWeek", ThisWeekRange())
-	fmt.Printf("%+10s %s\n", "LastWeek", LastWeekRange())
+	fmt.Printf("%+10s %s\n", "ThisWeek", ThisWeekRange(time.Sunday))
+	fmt.Printf("%+10s %s\n", "LastWeek", LastWeekRange(time.Sunday))
 	fmt.Printf("%+10s %s\n", "ThisMonth", ThisMonthRange())
 	fmt.Printf("%+10s %s\n", "LastMonth", LastMonthRange())
 	fmt.Printf("%+10s %s\n", "ThisYear", ThisYearRange())
@@ -59,12 +59,12 @@ func TestDateRanges(t *testing.T) {
 		t.Errorf("Yesterday -> want %s - %s, got %s - %s", YesterdayStart, YesterdayEnd, dr.Start, dr.End)
 	}
 
-	dr = ThisWeekRange()
+	dr = ThisWeekRange(time.Sunday)
 	if !dr.Start.Equal(parseUnixDate(ThisWeekStart, t)) || !dr.End.Equal(parseUnixDate(ThisWeekEnd, t)) {
 		t.Errorf("ThisWeek -> want %s - %s, got %s - %s", ThisWeekStart, ThisWeekEnd, dr.Start, dr.End)
 	}
 
-	dr = LastWeekRange()
+	dr = LastWeekRange(time.Sunday)
 	if !dr.Start.Equal(parseUnixDate(LastWeekStart, t)) || !dr.End.Equal(parseUnixDate(LastWeekEnd, t)) {
 		t.Errorf("LastWeek -> want %s - %s, got %s - %s", LastWeekStart, LastWeekEnd, dr.Start, dr.End)
 	}
@@ -156,6 +156,12 @@ func TestAfterNow(t *testing.T) {
 		t.Errorf("AfterNow(2) %s is within date range %+v", dt, dr)
 	}
 
+	// midnight UTC is still the previous day in -0500
+	loc := time.FixedZone("-0500", -5*3600)
+	dr = AfterNow(0, loc)
+	if want := time.Date(2015, 6, 30, 23, 59, 59, 999999999, loc); !dr.End.Equal(want) {
+		t.Errorf("AfterNow(0, %s) want end %s, got %s", loc, want, dr.End)
+	}
 }
 
 func TestStartOnlyRange(t *testing.T) {
@@ -223,3 +229,152 @@ func TestEndOnlyRange(t *testing.T) {
 		t.Errorf("dr.Within(%s) within %+v", testDate, dr)
 	}
 }
+
+func TestDateRangesInLocation(t *testing.T) {
+	tm, err := time.Parse(time.RFC3339, "2015-07-01T02:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveNow := Now
+	defer func() { Now = saveNow }()
+	Now = func() time.Time { return tm }
+
+	loc, err := ParseTimeZone("America/Chicago")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2am UTC on July 1st is still June 30th in Chicago
+	dr := TodayRange(loc)
+	want := time.Date(2015, 6, 30, 0, 0, 0, 0, loc)
+	if !dr.Start.Equal(want) {
+		t.Errorf("TodayRange(%s) -> want start %s, got %s", loc, want, dr.Start)
+	}
+	if !dr.Within(tm) {
+		t.Errorf("TodayRange(%s) -> %s not within %s", loc, tm, dr)
+	}
+
+	dr = TodayRange(time.UTC)
+	if !dr.Start.Equal(time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("TodayRange(UTC) -> want start 2015-07-01, got %s", dr.Start)
+	}
+}
+
+func TestParseTimeZone(t *testing.T) {
+	cases := []struct {
+		Name string
+		Want *time.Location
+	}{
+		{"", time.Local},
+		{"local", time.Local},
+		{"UTC", time.UTC},
+	}
+	for _, tc := range cases {
+		got, err := ParseTimeZone(tc.Name)
+		if err != nil {
+			t.Errorf("ParseTimeZone(%s), want error nil got %s", tc.Name, err)
+		}
+		if got != tc.Want {
+			t.Errorf("ParseTimeZone(%s), want %s got %s", tc.Name, tc.Want, got)
+		}
+	}
+
+	if _, err := ParseTimeZone("Not/AZone"); err == nil {
+		t.Errorf("ParseTimeZone(Not/AZone), want error got nil")
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	tm, err := time.Parse(time.RFC3339, "2015-06-28T12:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveNow := Now
+	defer func() { Now = saveNow }()
+	Now = func() time.Time { return tm.In(time.UTC) }
+
+	// June 28th 2015 is a sunday
+	dr := ThisWeekRange(time.Monday)
+	if want := time.Date(2015, 6, 22, 0, 0, 0, 0, time.UTC); !dr.Start.Equal(want) {
+		t.Errorf("ThisWeekRange() monday -> want start %s, got %s", want, dr.Start)
+	}
+	if want := time.Date(2015, 6, 29, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond); !dr.End.Equal(want) {
+		t.Errorf("ThisWeekRange() monday -> want end %s, got %s", want, dr.End)
+	}
+	dr = LastWeekRange(time.Monday)
+	if want := time.Date(2015, 6, 15, 0, 0, 0, 0, time.UTC); !dr.Start.Equal(want) {
+		t.Errorf("LastWeekRange() monday -> want start %s, got %s", want, dr.Start)
+	}
+
+	dr = ThisWeekRange(time.Sunday)
+	if want := time.Date(2015, 6, 28, 0, 0, 0, 0, time.UTC); !dr.Start.Equal(want) {
+		t.Errorf("ThisWeekRange() sunday -> want start %s, got %s", want, dr.Start)
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	cases := map[string]time.Weekday{"monday": time.Monday, "Sun": time.Sunday, " sat ": time.Saturday}
+	for name, want := range cases {
+		got, err := ParseWeekday(name)
+		if err != nil || got != want {
+			t.Errorf("ParseWeekday(%s), want %s got %s, %v", name, want, got, err)
+		}
+	}
+	if _, err := ParseWeekday("someday"); err == nil {
+		t.Errorf("ParseWeekday(someday), want error got nil")
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	loc := time.FixedZone("-0500", -5*3600)
+	// late evening in -0500 is the next day in UTC
+	tm := time.Date(2015, 8, 30, 22, 0, 0, 0, loc)
+
+	cases := []struct {
+		Period string
+		Start  time.Time
+		Label  string
+	}{
+		{PeriodDay, time.Date(2015, 8, 30, 0, 0, 0, 0, loc), "Sun Aug 30 2015"},
+		{PeriodWeek, time.Date(2015, 8, 24, 0, 0, 0, 0, loc), "Week of Aug 24 2015"},
+		{PeriodMonth, time.Date(2015, 8, 1, 0, 0, 0, 0, loc), "August 2015"},
+		{PeriodQuarter, time.Date(2015, 7, 1, 0, 0, 0, 0, loc), "2015 Q3"},
+		{PeriodYear, time.Date(2015, 1, 1, 0, 0, 0, 0, loc), "2015"},
+	}
+	for _, tc := range cases {
+		if got := PeriodStart(tc.Period, tm, time.Monday); !got.Equal(tc.Start) {
+			t.Errorf("PeriodStart(%s), want %s got %s", tc.Period, tc.Start, got)
+		}
+		if got := PeriodLabel(tc.Period, tm, time.Monday); got != tc.Label {
+			t.Errorf("PeriodLabel(%s), want %s got %s", tc.Period, tc.Label, got)
+		}
+	}
+}
+
+func TestPrevious(t *testing.T) {
+	loc := time.FixedZone("-0500", -5*3600)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, loc) }
+	thru := func(y int, m time.Month, d int) time.Time { return day(y, m, d).AddDate(0, 0, 1).Add(-time.Nanosecond) }
+
+	cases := []struct {
+		Range    DateRange
+		Previous DateRange
+	}{
+		// week
+		{DateRange{Start: day(2015, 6, 28), End: thru(2015, 7, 4)}, DateRange{Start: day(2015, 6, 21), End: thru(2015, 6, 27)}},
+		// months have different lengths
+		{DateRange{Start: day(2015, 3, 1), End: thru(2015, 3, 31)}, DateRange{Start: day(2015, 2, 1), End: thru(2015, 2, 28)}},
+		// quarter
+		{DateRange{Start: day(2015, 4, 1), End: thru(2015, 6, 30)}, DateRange{Start: day(2015, 1, 1), End: thru(2015, 3, 31)}},
+		// year
+		{DateRange{Start: day(2016, 1, 1), End: thru(2016, 12, 31)}, DateRange{Start: day(2015, 1, 1), End: thru(2015, 12, 31)}},
+		// days
+		{DateRange{Start: day(2015, 3, 10), End: thru(2015, 3, 14)}, DateRange{Start: day(2015, 3, 5), End: thru(2015, 3, 9)}},
+	}
+	for _, tc := range cases {
+		got := tc.Range.Previous()
+		if !got.Start.Equal(tc.Previous.Start) || !got.End.Equal(tc.Previous.End) {
+			t.Errorf("Previous(%s), want %s got %s", tc.Range, tc.Previous, got)
+		}
+	}
+}
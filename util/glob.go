@@ -0,0 +1,50 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchGlob matches a slash separated file path to a glob.
+// A glob without a slash matches the file's name, a glob ending in / or /** matches a directory
+// at any depth, or only at the root if the glob starts with ./, and any other glob matches the file's path.
+func MatchGlob(glob, file string) bool {
+	anchored := strings.HasPrefix(glob, "./")
+	glob = strings.TrimPrefix(glob, "./")
+	file = strings.TrimPrefix(file, "./")
+
+	switch {
+	case strings.HasSuffix(glob, "/**") || strings.HasSuffix(glob, "/"):
+		dir := strings.TrimSuffix(strings.TrimSuffix(glob, "**"), "/") + "/"
+		return strings.HasPrefix(file, dir) || (!anchored && strings.Contains(file, "/"+dir))
+	case !anchored && !strings.Contains(glob, "/"):
+		ok, _ := path.Match(glob, path.Base(file))
+		return ok
+	default:
+		ok, _ := path.Match(glob, file)
+		return ok
+	}
+}
+
+// MatchAnyGlob returns true if a slash separated file path matches any of the globs
+func MatchAnyGlob(globs []string, file string) bool {
+	for _, g := range globs {
+		if MatchGlob(g, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidGlob returns an error if the glob's pattern is malformed
+func ValidGlob(glob string) error {
+	if _, err := path.Match(glob, ""); err != nil {
+		return fmt.Errorf("Glob %s not valid, %s", glob, err)
+	}
+	return nil
+}
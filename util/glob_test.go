@@ -0,0 +1,37 @@
+// Copyright 2016 Michael Schenk. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package util
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		glob, file string
+		want       bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", true},
+		{"*.go", "main.js", false},
+		{"cmd/*.go", "cmd/main.go", true},
+		{"cmd/*.go", "x/cmd/main.go", false},
+		{"vendor/**", "vendor/a/b.go", true},
+		{"vendor/**", "web/vendor/b.js", true},
+		{"vendor/", "web/vendor/b.js", true},
+		{"vendor/", "vendors/b.js", false},
+		{"./vendor/", "vendor/a/b.go", true},
+		{"./vendor/", "web/vendor/b.js", false},
+		{"./main.go", "main.go", true},
+		{"./main.go", "cmd/main.go", false},
+	}
+	for _, tc := range tests {
+		if got := MatchGlob(tc.glob, tc.file); got != tc.want {
+			t.Errorf("MatchGlob(%s, %s), want %t got %t", tc.glob, tc.file, tc.want, got)
+		}
+	}
+
+	if err := ValidGlob("[a-"); err == nil {
+		t.Errorf("ValidGlob([a-), want error got nil")
+	}
+}
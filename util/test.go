@@ -20,7 +20,7 @@ import (
 // TestRepo represents a test git repo used in testing
 type TestRepo struct {
 	repo *git.Repository
-	test *testing.T
+	test testing.TB
 }
 
 // Repo return a pointer to the git repository
@@ -29,7 +29,7 @@ func (t TestRepo) Repo() *git.Repository {
 }
 
 // NewTestRepo creates a new instance of TestRepo
-func NewTestRepo(t *testing.T, bare bool) TestRepo {
+func NewTestRepo(t testing.TB, bare bool) TestRepo {
 	path, err := ioutil.TempDir("", "gtm")
 	CheckFatal(t, err)
 	repo, err := git.InitRepository(path, bare)
@@ -187,7 +187,7 @@ func (t TestRepo) Fetch(name string, refs ...string) {
 }
 
 // CheckFatal raises a fatal error if error is not nil
-func CheckFatal(t *testing.T, err error) {
+func CheckFatal(t testing.TB, err error) {
 	if err == nil {
 		return
 	}